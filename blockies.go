@@ -0,0 +1,134 @@
+package ppic
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"unicode/utf16"
+)
+
+// Values of the cells in a blockies grid.
+const (
+	BlockiesBackground = iota
+	BlockiesColor
+	BlockiesSpot
+)
+
+// BlockiesPalette represents the three colors used in blockies image generation.
+type BlockiesPalette struct {
+	Color      color.Color
+	Background color.Color
+	Spot       color.Color
+}
+
+// Palette returns a color palette indexed by the values of the cells in a blockies grid.
+func (p BlockiesPalette) Palette() color.Palette {
+	return color.Palette{
+		BlockiesBackground: p.Background,
+		BlockiesColor:      p.Color,
+		BlockiesSpot:       p.Spot,
+	}
+}
+
+// blockiesRand is the xorshift random number generator used by the ethereum-blockies library.
+//
+// The library stores the state as JavaScript numbers, but as every operation on them truncates to 32 bits it behaves
+// the same as working with 32 bit integers.
+type blockiesRand [4]uint32
+
+// newBlockiesRand returns a random number generator seeded from the provided string.
+func newBlockiesRand(seed string) *blockiesRand {
+	r := blockiesRand{}
+
+	// JavaScript strings are indexed by UTF-16 code unit.
+	for i, c := range utf16.Encode([]rune(seed)) {
+		r[i%4] = r[i%4]<<5 - r[i%4] + uint32(c)
+	}
+
+	return &r
+}
+
+// next returns the next random number, between 0 and 1.
+func (r *blockiesRand) next() float64 {
+	t := r[0] ^ r[0]<<11
+
+	r[0], r[1], r[2] = r[1], r[2], r[3]
+
+	// The right shifts are arithmetic in JavaScript.
+	r[3] = r[3] ^ uint32(int32(r[3])>>19) ^ t ^ uint32(int32(t)>>8)
+
+	return float64(r[3]) / (1 << 31)
+}
+
+// color returns the next random color, in the same way as the library's createColor.
+func (r *blockiesRand) color() color.Color {
+	h := math.Floor(r.next() * 360)
+	s := r.next()*60 + 40
+	l := (r.next() + r.next() + r.next() + r.next()) * 25
+
+	return hslToRGB(h, s/100, l/100)
+}
+
+// palette returns the random palette in the order that the library generates it.
+func (r *blockiesRand) palette() BlockiesPalette {
+	p := BlockiesPalette{}
+
+	p.Color = r.color()
+	p.Background = r.color()
+	p.Spot = r.color()
+
+	return p
+}
+
+// GenerateBlockiesPalette generates a color palette from a seed, matching the ethereum-blockies library.
+//
+// Ethereum addresses are conventionally lowercased before being used as the seed. Unlike ethereum-blockies (which picks
+// a random seed), an empty seed always gives a black palette.
+func GenerateBlockiesPalette(seed string) BlockiesPalette {
+	return newBlockiesRand(seed).palette()
+}
+
+// GenerateBlockies returns an 8x8 grid of cell values (BlockiesBackground, BlockiesColor or BlockiesSpot) based on the
+// provided seed, matching the ethereum-blockies library.
+func GenerateBlockies(seed string) (grid [8][8]uint8) {
+	r := newBlockiesRand(seed)
+
+	// The palette is generated before the grid, so we need to skip past it.
+	r.palette()
+
+	for y := range grid {
+		for x := 0; x < 4; x++ {
+			// This gives a roughly 43% chance of background, 43% chance of color and 13% chance of spot color.
+			v := uint8(math.Floor(r.next() * 2.3))
+
+			grid[y][x] = v
+			grid[y][7-x] = v
+		}
+	}
+
+	return grid
+}
+
+// GenerateBlockiesImage returns an image for the specified blockies grid.
+func GenerateBlockiesImage(grid [8][8]uint8, size int, p BlockiesPalette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	// The size of each pixel in the image.
+	pSize := size / 8
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), p.Palette())
+
+	for y, row := range grid {
+		for x, val := range row {
+			for cY := y * pSize; cY < (y+1)*pSize; cY++ {
+				for cX := x * pSize; cX < (x+1)*pSize; cX++ {
+					img.Pix[cY*img.Stride+cX] = val
+				}
+			}
+		}
+	}
+
+	return img, nil
+}
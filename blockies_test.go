@@ -0,0 +1,230 @@
+package ppic_test
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+// blockiesCases contains the output of the ethereum-blockies JavaScript library for a set of seeds, with the colors
+// converted to RGB in the same way as a browser would.
+var blockiesCases = []struct {
+	seed       string
+	color      string
+	background string
+	spot       string
+	grid       [8]string
+}{
+	{
+		seed:       "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359",
+		color:      "#faad15",
+		background: "#e7ed33",
+		spot:       "#7369f8",
+		grid: [8]string{
+			"10000001",
+			"00000000",
+			"11000011",
+			"12111121",
+			"01011010",
+			"02122120",
+			"02000020",
+			"10211201",
+		},
+	},
+	{
+		seed:       "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		color:      "#1341ae",
+		background: "#0efeae",
+		spot:       "#416d99",
+		grid: [8]string{
+			"22111122",
+			"01022010",
+			"01100110",
+			"00111100",
+			"00000000",
+			"01211210",
+			"00122100",
+			"10100101",
+		},
+	},
+	{
+		seed:       "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae",
+		color:      "#92dc52",
+		background: "#3db8e9",
+		spot:       "#fe30c7",
+		grid: [8]string{
+			"01000010",
+			"10200201",
+			"12111121",
+			"11000011",
+			"21100112",
+			"02100120",
+			"10111101",
+			"11000011",
+		},
+	},
+	{
+		seed:       "0x0000000000000000000000000000000000000000",
+		color:      "#da4554",
+		background: "#2c76d1",
+		spot:       "#c87ba5",
+		grid: [8]string{
+			"00100100",
+			"10000001",
+			"01100110",
+			"21111112",
+			"10122101",
+			"10011001",
+			"20000002",
+			"00211200",
+		},
+	},
+	{
+		seed:       "0xd8da6bf26964af9d7eed9e03e53415d37aa96045",
+		color:      "#0101fb",
+		background: "#561e3f",
+		spot:       "#37faa9",
+		grid: [8]string{
+			"01111110",
+			"12022021",
+			"11122111",
+			"01000010",
+			"01000010",
+			"01100110",
+			"00000000",
+			"02111120",
+		},
+	},
+	{
+		seed:       "0xab5801a7d398351b8be11c439e05c5b3259aec9b",
+		color:      "#b43f5f",
+		background: "#e43302",
+		spot:       "#8ddecf",
+		grid: [8]string{
+			"00200200",
+			"11000011",
+			"01122110",
+			"21211212",
+			"21000012",
+			"10111101",
+			"10200201",
+			"00200200",
+		},
+	},
+	{
+		seed:       "héllo ☃ 😀",
+		color:      "#938721",
+		background: "#d76bec",
+		spot:       "#efd8d1",
+		grid: [8]string{
+			"00111100",
+			"11100111",
+			"10000001",
+			"02200220",
+			"10200201",
+			"21111112",
+			"11100111",
+			"01111110",
+		},
+	},
+}
+
+func TestGenerateBlockies(t *testing.T) {
+	for i, c := range blockiesCases {
+		c := c
+
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			grid := ppic.GenerateBlockies(c.seed)
+
+			for y, row := range grid {
+				for x, act := range row {
+					if exp := c.grid[y][x] - '0'; act != exp {
+						t.Errorf("expected grid[%d][%d] to be %d but got %d", y, x, exp, act)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestGenerateBlockiesEmptySeed checks the output for an empty seed, which isn't compared against ethereum-blockies as
+// the library picks a random seed instead. This implementation always seeds its generator with zeros, which gives a
+// black palette and an empty grid.
+func TestGenerateBlockiesEmptySeed(t *testing.T) {
+	if grid := ppic.GenerateBlockies(""); grid != [8][8]uint8{} {
+		t.Errorf("expected grid to be empty but got %v", grid)
+	}
+
+	p := ppic.GenerateBlockiesPalette("")
+
+	for _, c := range []color.Color{p.Color, p.Background, p.Spot} {
+		if !colorsEqual(c, color.Black) {
+			t.Errorf("expected color to be black but got %v", c)
+		}
+	}
+}
+
+// parseHex parses a color in the format #RRGGBB.
+func parseHex(t *testing.T, s string) color.RGBA {
+	c := color.RGBA{A: 0xFF}
+
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		t.Fatalf("failed to parse color %q: %s", s, err)
+	}
+
+	return c
+}
+
+func TestGenerateBlockiesPalette(t *testing.T) {
+	for i, c := range blockiesCases {
+		c := c
+
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			p := ppic.GenerateBlockiesPalette(c.seed)
+
+			cases := []struct {
+				name     string
+				expected color.Color
+				actual   color.Color
+			}{
+				{"color", parseHex(t, c.color), p.Color},
+				{"background", parseHex(t, c.background), p.Background},
+				{"spot", parseHex(t, c.spot), p.Spot},
+			}
+
+			for _, pc := range cases {
+				if !colorsEqual(pc.expected, pc.actual) {
+					t.Errorf("expected %s to be %v but got %v", pc.name, pc.expected, pc.actual)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBlockiesImage(t *testing.T) {
+	p := ppic.GenerateBlockiesPalette(blockiesCases[0].seed)
+	grid := ppic.GenerateBlockies(blockiesCases[0].seed)
+	img, err := ppic.GenerateBlockiesImage(grid, 64, p)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pal := p.Palette()
+
+	for y, row := range grid {
+		for x, val := range row {
+			if act := img.At(x*8+4, y*8+4); !colorsEqual(act, pal[val]) {
+				t.Errorf("expected cell (%d, %d) to be %v but got %v", x, y, pal[val], act)
+			}
+		}
+	}
+
+	for _, size := range []int{31, 0, -8} {
+		if _, err := ppic.GenerateBlockiesImage(grid, size, p); err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
-	"image/png"
 	"os"
 	"path"
 	"strconv"
@@ -14,47 +15,242 @@ import (
 func main() {
 	cmd := path.Base(os.Args[0])
 
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "usage: %s text [size] > image.png\n", cmd)
+	// Build a list of the flags we support.
+	style := flag.String("style", ppic.DefaultStyle, "image style ("+humanList(ppic.Styles())+")")
+	formats := append(encoderNames(), "svg", "stl", "glb", "txt")
+	format := flag.String("format", "png", "output format ("+humanList(formats)+")")
+	datauri := flag.Bool("datauri", false, "output the image as a data URI")
+	parts := flag.String("parts", "", "directory to load the parts used by the parts style from")
+	opts := optionsFlag{}
+	flag.Var(opts, "option", "style option as key=value (such as name=, set=, keytype= or bits=, can be repeated)")
+	encodeOpts := optionsFlag{}
+	flag.Var(encodeOpts, "encode",
+		"encoder option as key=value (such as quality=, compression= or colors=, can be repeated)")
+	useColor := flag.Bool("color", false, "use the style's colors instead of black and white")
+	flag.String("fg", "", "foreground color (hex, a CSS named color, rgb() or hsl())")
+	flag.String("bg", "", "background color (hex, a CSS named color, rgb() or hsl())")
+	preview := flag.String("preview", "",
+		"preview the image in the terminal using the specified protocol (sixel, kitty or iterm)")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] text [size] > image.png\n\n", cmd)
+		flag.PrintDefaults()
+	}
+
+	// Parse the command-line flags.
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
 		os.Exit(1)
 	}
 
-	txt := os.Args[1]
+	txt := flag.Arg(0)
 	size := 512
 
-	if len(os.Args) > 2 {
+	if flag.NArg() > 1 {
 		var err error
 
-		size, err = strconv.Atoi(os.Args[2])
+		size, err = strconv.Atoi(flag.Arg(1))
 
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s: invalid size %q\n", cmd, os.Args[2])
+			fmt.Fprintf(os.Stderr, "%s: invalid size %q\n", cmd, flag.Arg(1))
+			os.Exit(1)
+		}
+	}
+
+	var previewer previewWriter
+
+	// Look up the preview protocol if one was specified.
+	if len(*preview) > 0 {
+		previewer = getPreviewWriter(*preview)
+
+		if previewer == nil {
+			fmt.Fprintf(os.Stderr, "%s: unsupported preview protocol %q\n", cmd, *preview)
+			os.Exit(1)
+		}
+	}
+
+	// Load the parts from a directory if one was specified, registering them under the name of the directory.
+	if len(*parts) > 0 {
+		if err := ppic.RegisterPartSetDir(*parts, *parts); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: failed to load parts: %s\n", cmd, err)
 			os.Exit(1)
 		}
+
+		opts["set"] = *parts
+	}
+
+	generator, ok := ppic.LookupStyle(*style)
+
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s: unsupported style %q\n", cmd, *style)
+		os.Exit(1)
+	}
+
+	generator, err := ppic.ConfigureStyle(generator, opts)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: failed to configure style %q: %s\n", cmd, *style, err)
+		os.Exit(1)
+	}
+
+	colors := map[string]string{}
+
+	// Only override the colors which were specified.
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "fg" || f.Name == "bg" {
+			colors[f.Name] = f.Value.String()
+		}
+	})
+
+	pal, err := ppic.StylePalette(generator, txt, !*useColor, colors)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", cmd, err)
+		os.Exit(1)
 	}
 
-	// If we're trying to output to a terminal then prevent it.
-	if isTerminal() {
+	svgGenerator, _ := generator.(ppic.SVGStyle)
+	textGenerator, _ := generator.(ppic.TextStyle)
+
+	var encoder ppic.Encoder
+
+	isImage := false
+
+	// Check that we support the output format.
+	switch *format {
+	case "svg":
+		if previewer != nil {
+			fmt.Fprintf(os.Stderr, "%s: -preview cannot be used with SVG output\n", cmd)
+			os.Exit(1)
+		}
+
+		if svgGenerator == nil {
+			fmt.Fprintf(os.Stderr, "%s: svg output is only supported for the %s styles\n", cmd, humanList(svgStyles()))
+			os.Exit(1)
+		}
+	case "stl", "glb":
+		if previewer != nil {
+			fmt.Fprintf(os.Stderr, "%s: -preview cannot be used with 3D models\n", cmd)
+			os.Exit(1)
+		}
+
+		if len(*style) > 0 && !strings.EqualFold(*style, ppic.DefaultStyle) {
+			fmt.Fprintf(os.Stderr, "%s: -style cannot be used with 3D models\n", cmd)
+			os.Exit(1)
+		}
+	case "txt":
+		if textGenerator == nil || previewer != nil || *datauri {
+			fmt.Fprintf(os.Stderr, "%s: txt output is not supported for the %s style\n", cmd, *style)
+			os.Exit(1)
+		}
+	default:
+		// Anything else needs to be encoded from an image.
+		encoder, isImage = ppic.LookupEncoder(*format)
+
+		if !isImage {
+			fmt.Fprintf(os.Stderr, "%s: unsupported format %q\n", cmd, *format)
+			os.Exit(1)
+		}
+	}
+
+	if previewer != nil && *datauri {
+		fmt.Fprintf(os.Stderr, "%s: -preview and -datauri cannot be used together\n", cmd)
+		os.Exit(1)
+	}
+
+	// Text can be written straight to the terminal.
+	if *format == "txt" {
+		fmt.Println(textGenerator.Text(txt))
+
+		return
+	}
+
+	// If we're trying to output to a terminal then prevent it (unless we're previewing or outputting text).
+	if previewer == nil && !*datauri && isTerminal() {
 		fmt.Fprintf(os.Stderr, "%s: refusing to output image to stdout (it looks like a terminal!)\n", cmd)
 
 		args := strings.Join(os.Args[1:], " ")
-		fmt.Fprintf(os.Stderr, "\ntry piping the output to a file:\n\t%s %s > image.png\n", cmd, args)
+		fmt.Fprintf(os.Stderr, "\ntry piping the output to a file:\n\t%s %s > image.%s\n", cmd, args, *format)
+		fmt.Fprintf(os.Stderr, "\nor previewing it in the terminal:\n\t%s -preview=sixel %s\n", cmd, args)
 
 		os.Exit(1)
 	}
 
-	grid := ppic.Generate(txt, true, false)
-	img, err := ppic.GenerateImage(grid, size, ppic.DefaultPalette)
+	// SVG documents and 3D models are generated directly rather than being encoded from an image.
+	if !isImage {
+		buf := bytes.Buffer{}
+		contentType := "model/stl"
+
+		var err error
+
+		if *format == "svg" {
+			var doc string
+
+			contentType = "image/svg+xml"
+			doc, err = svgGenerator.SVG(txt, size, pal)
+			buf.WriteString(doc)
+		} else {
+			mesh := ppic.GenerateMesh(ppic.Generate(txt, true, false), ppic.DefaultMeshOptions)
+
+			if *format == "glb" {
+				contentType = "model/gltf-binary"
+				err = mesh.WriteGLB(&buf, pal)
+			} else {
+				err = mesh.WriteSTL(&buf)
+			}
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: failed to generate %s: %s\n", cmd, *format, err)
+			os.Exit(1)
+		}
+
+		if *datauri {
+			fmt.Println(ppic.DataURI(contentType, buf.Bytes()))
+		} else {
+			os.Stdout.Write(buf.Bytes())
+		}
+
+		return
+	}
+
+	img, err := generator.Image(txt, size, pal)
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%s: failed to generate image: %s\n", cmd, err)
 		os.Exit(1)
 	}
 
-	err = png.Encode(os.Stdout, img)
+	if *datauri {
+		buf := bytes.Buffer{}
+
+		if err = encoder.Encode(&buf, img, ppic.EncodeOptions(encodeOpts)); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: failed to generate data URI: %s\n", cmd, err)
+			os.Exit(1)
+		}
+
+		fmt.Println(ppic.DataURI(encoder.MIMEType, buf.Bytes()))
+
+		return
+	}
+
+	if previewer != nil {
+		err = previewer(os.Stdout, img)
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: failed to preview image: %s\n", cmd, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	err = encoder.Encode(os.Stdout, img, ppic.EncodeOptions(encodeOpts))
 
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s: failed to generate PNG: %s\n", cmd, err)
+		fmt.Fprintf(os.Stderr, "%s: failed to generate %s: %s\n", cmd, encoder.Name, err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+// kittyChunkSize is the maximum amount of base64 data sent in a single kitty graphics escape sequence.
+const kittyChunkSize = 4096
+
+// previewWriter represents a function which can write an image to a terminal.
+type previewWriter func(io.Writer, image.Image) error
+
+// getPreviewWriter returns a previewWriter for the specified protocol.
+func getPreviewWriter(p string) previewWriter {
+	switch strings.ToLower(p) {
+	case "sixel":
+		return writeSixel
+	case "kitty":
+		return writeKitty
+	case "iterm", "iterm2":
+		return writeITerm
+	default:
+		return nil
+	}
+}
+
+// toPaletted converts an image to a paletted image, quantizing it if it isn't already paletted.
+func toPaletted(img image.Image) *image.Paletted {
+	if p, ok := img.(*image.Paletted); ok {
+		return p
+	}
+
+	// Other paletted images (such as ppic.GridImage) can keep their palette.
+	if pi, ok := img.(image.PalettedImage); ok {
+		if pal, ok := pi.ColorModel().(color.Palette); ok {
+			return ppic.ToPaletted(pi, pal)
+		}
+	}
+
+	p := image.NewPaletted(img.Bounds(), palette.Plan9)
+	draw.Draw(p, p.Rect, img, img.Bounds().Min, draw.Src)
+
+	return p
+}
+
+// writeSixelRun writes a run of identical sixels, using run-length encoding if it's shorter.
+func writeSixelRun(w *bufio.Writer, c byte, n int) {
+	if n > 3 {
+		fmt.Fprintf(w, "!%d%c", n, c)
+
+		return
+	}
+
+	for i := 0; i < n; i++ {
+		w.WriteByte(c)
+	}
+}
+
+// writeSixel writes an image to a terminal using the DEC sixel graphics protocol.
+func writeSixel(out io.Writer, img image.Image) error {
+	p := toPaletted(img)
+	b := p.Rect
+	w := bufio.NewWriter(out)
+
+	// Start the sixel sequence and specify the raster attributes.
+	fmt.Fprintf(w, "\x1bPq\"1;1;%d;%d", b.Dx(), b.Dy())
+
+	// Define each color in the palette (the components are percentages).
+	for i, c := range p.Palette {
+		r, g, b, _ := c.RGBA()
+
+		fmt.Fprintf(w, "#%d;2;%d;%d;%d", i, (r*100+0x7FFF)/0xFFFF, (g*100+0x7FFF)/0xFFFF, (b*100+0x7FFF)/0xFFFF)
+	}
+
+	// Each band of sixels covers 6 rows of pixels.
+	for y := b.Min.Y; y < b.Max.Y; y += 6 {
+		// Work out which colors are used in this band so we can skip the others.
+		used := make([]bool, len(p.Palette))
+
+		for dy := 0; dy < 6 && y+dy < b.Max.Y; dy++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				used[p.ColorIndexAt(x, y+dy)] = true
+			}
+		}
+
+		first := true
+
+		for i, u := range used {
+			if !u {
+				continue
+			}
+
+			// Return to the start of the band before drawing the next color.
+			if !first {
+				w.WriteByte('$')
+			}
+
+			first = false
+
+			fmt.Fprintf(w, "#%d", i)
+
+			var last byte
+			run := 0
+
+			for x := b.Min.X; x < b.Max.X; x++ {
+				var bits byte
+
+				for dy := 0; dy < 6 && y+dy < b.Max.Y; dy++ {
+					if int(p.ColorIndexAt(x, y+dy)) == i {
+						bits |= 1 << uint(dy)
+					}
+				}
+
+				c := '?' + bits
+
+				if run > 0 && c != last {
+					writeSixelRun(w, last, run)
+					run = 0
+				}
+
+				last = c
+				run++
+			}
+
+			writeSixelRun(w, last, run)
+		}
+
+		// Move on to the next band.
+		w.WriteByte('-')
+	}
+
+	// End the sixel sequence.
+	w.WriteString("\x1b\\\n")
+
+	return w.Flush()
+}
+
+// encodePNG returns the base64 encoded PNG representation of an image.
+func encodePNG(img image.Image) (string, error) {
+	buf := bytes.Buffer{}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// writeKitty writes an image to a terminal using the kitty graphics protocol.
+func writeKitty(out io.Writer, img image.Image) error {
+	data, err := encodePNG(img)
+
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(out)
+
+	// The data has to be sent in chunks, with every chunk except the last one specifying m=1.
+	for i := 0; i < len(data); i += kittyChunkSize {
+		end := i + kittyChunkSize
+		more := 1
+
+		if end >= len(data) {
+			end = len(data)
+			more = 0
+		}
+
+		// Only the first chunk needs to specify the format and action.
+		if i == 0 {
+			fmt.Fprintf(w, "\x1b_Gf=100,a=T,m=%d;%s\x1b\\", more, data[i:end])
+		} else {
+			fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+
+	w.WriteByte('\n')
+
+	return w.Flush()
+}
+
+// writeITerm writes an image to a terminal using the iTerm2 inline image protocol.
+func writeITerm(out io.Writer, img image.Image) error {
+	data, err := encodePNG(img)
+
+	if err != nil {
+		return err
+	}
+
+	b := img.Bounds()
+
+	_, err = fmt.Fprintf(out, "\x1b]1337;File=inline=1;width=%dpx;height=%dpx:%s\a\n", b.Dx(), b.Dy(), data)
+
+	return err
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+// optionsFlag is a flag which collects "key=value" options each time it's specified.
+type optionsFlag map[string]string
+
+// String returns the options as a comma separated list.
+func (o optionsFlag) String() string {
+	opts := make([]string, 0, len(o))
+
+	for k, v := range o {
+		opts = append(opts, k+"="+v)
+	}
+
+	sort.Strings(opts)
+
+	return strings.Join(opts, ",")
+}
+
+// Set adds an option specified as "key=value".
+func (o optionsFlag) Set(v string) error {
+	i := strings.IndexRune(v, '=')
+
+	if i < 1 {
+		return fmt.Errorf("expected key=value but got %q", v)
+	}
+
+	o[v[:i]] = v[i+1:]
+
+	return nil
+}
+
+// humanList joins a list of names into a human readable list (such as "a, b or c").
+func humanList(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// svgStyles returns the names of the registered styles which support SVG output.
+func svgStyles() []string {
+	var names []string
+
+	for _, name := range ppic.Styles() {
+		if s, _ := ppic.LookupStyle(name); s != nil {
+			if _, ok := s.(ppic.SVGStyle); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+// encoderNames returns the names of the registered image encoders.
+func encoderNames() []string {
+	var names []string
+
+	for _, e := range ppic.Encoders() {
+		names = append(names, e.Name)
+	}
+
+	return names
+}
@@ -3,11 +3,16 @@ package main
 import (
 	"flag"
 	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
 	"log"
 	"net"
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/felixge/httpsnoop"
 	"github.com/jackwilsdon/go-ppic"
@@ -43,6 +48,150 @@ func withLogger(h http.Handler) http.Handler {
 	})
 }
 
+// partSetsFlag is a flag which registers a part set from a directory each time it's specified.
+type partSetsFlag struct{}
+
+// String returns the default value of the flag.
+func (partSetsFlag) String() string {
+	return ""
+}
+
+// Set registers the part set specified as "name=dir".
+func (partSetsFlag) Set(v string) error {
+	i := strings.IndexRune(v, '=')
+
+	if i < 1 {
+		return fmt.Errorf("expected name=dir but got %q", v)
+	}
+
+	return ppic.RegisterPartSetDir(v[:i], v[i+1:])
+}
+
+// parseEncoderOption parses an encoder option specified as "encoder.option=value", checking that the encoder exists and
+// accepts the option.
+func parseEncoderOption(v string) (ppic.Encoder, string, string, error) {
+	i := strings.IndexRune(v, '.')
+	j := strings.IndexRune(v, '=')
+
+	if i < 1 || j < i+2 {
+		return ppic.Encoder{}, "", "", fmt.Errorf("expected encoder.option=value but got %q", v)
+	}
+
+	for _, e := range ppic.Encoders() {
+		if !strings.EqualFold(e.Name, v[:i]) {
+			continue
+		}
+
+		for _, opt := range e.Options {
+			if opt == v[i+1:j] {
+				return e, opt, v[j+1:], nil
+			}
+		}
+
+		return ppic.Encoder{}, "", "", fmt.Errorf("unsupported %s option %q", e.Name, v[i+1:j])
+	}
+
+	return ppic.Encoder{}, "", "", fmt.Errorf("unsupported encoder %q", v[:i])
+}
+
+// checkEncodeLimit checks that an encoder accepts both ends of a limit for an option by encoding a tiny image with each
+// of them, which catches limits on options which aren't numeric.
+func checkEncodeLimit(e ppic.Encoder, opt string, l ppic.EncodeLimit) error {
+	img := image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.Black, color.White})
+
+	for _, n := range []int{l.Min, l.Max} {
+		err := e.Encode(ioutil.Discard, img, ppic.EncodeOptions{opt: strconv.Itoa(n)})
+
+		if _, ok := err.(*ppic.EncodeOptionError); ok {
+			return fmt.Errorf("%s option %q doesn't accept %d", e.Name, opt, n)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeOptionsFlag is a flag which sets a default encoder option each time it's specified.
+type encodeOptionsFlag map[string]ppic.EncodeOptions
+
+// String returns the default value of the flag.
+func (encodeOptionsFlag) String() string {
+	return ""
+}
+
+// Set sets the default encoder option specified as "encoder.option=value".
+func (f encodeOptionsFlag) Set(v string) error {
+	e, opt, value, err := parseEncoderOption(v)
+
+	if err != nil {
+		return err
+	}
+
+	if f[e.Name] == nil {
+		f[e.Name] = ppic.EncodeOptions{}
+	}
+
+	f[e.Name][opt] = value
+
+	return nil
+}
+
+// encodeLimitsFlag is a flag which limits the values of an encoder option each time it's specified.
+type encodeLimitsFlag map[string]map[string]ppic.EncodeLimit
+
+// String returns the default value of the flag.
+func (encodeLimitsFlag) String() string {
+	return ""
+}
+
+// Set sets the encoder option limit specified as "encoder.option=min:max".
+func (f encodeLimitsFlag) Set(v string) error {
+	e, opt, value, err := parseEncoderOption(v)
+
+	if err != nil {
+		return err
+	}
+
+	i := strings.IndexRune(value, ':')
+
+	if i < 0 {
+		return fmt.Errorf("expected min:max but got %q", value)
+	}
+
+	min, err := strconv.Atoi(value[:i])
+
+	if err != nil {
+		return fmt.Errorf("invalid minimum %q", value[:i])
+	}
+
+	max, err := strconv.Atoi(value[i+1:])
+
+	if err != nil {
+		return fmt.Errorf("invalid maximum %q", value[i+1:])
+	}
+
+	if min > max {
+		return fmt.Errorf("minimum %d is greater than maximum %d", min, max)
+	}
+
+	l := ppic.EncodeLimit{Min: min, Max: max}
+
+	if err := checkEncodeLimit(e, opt, l); err != nil {
+		return err
+	}
+
+	if f[e.Name] == nil {
+		f[e.Name] = map[string]ppic.EncodeLimit{}
+	}
+
+	f[e.Name][opt] = l
+
+	return nil
+}
+
 func main() {
 	// Build a list of the flags we support.
 	host := flag.String("h", "", "host to run the server on")
@@ -50,13 +199,48 @@ func main() {
 	debug := flag.Bool("d", false, "enable pprof debug routes")
 	gzip := flag.Bool("z", false, "enable gzip compression")
 	verbose := flag.Bool("v", false, "enable verbose output")
+	style := flag.String("style", ppic.DefaultStyle,
+		"style used when a request doesn't specify one ("+strings.Join(ppic.Styles(), ", ")+")")
+	flag.Var(partSetsFlag{}, "parts",
+		"register a part set for the parts style from a directory (as name=dir, can be repeated)")
+	encodeOpts := encodeOptionsFlag{}
+	flag.Var(encodeOpts, "encode",
+		"default encoder option (as encoder.option=value, such as png.compression=best, can be repeated)")
+	encodeLimits := encodeLimitsFlag{}
+	flag.Var(encodeLimits, "limit", "limit the values requests can use for a numeric encoder option "+
+		"(as encoder.option=min:max, such as jpeg.quality=10:90, can be repeated)")
+	renderPath := flag.String("render", "",
+		"path to serve images rendered from grids on, such as /render (disabled if empty)")
 
 	// Parse the command-line flags.
 	flag.Parse()
 
+	if _, ok := ppic.LookupStyle(*style); !ok {
+		log.Fatalf("error: unsupported style %q\n", *style)
+	}
+
 	// Create a new server with our handler.
 	mux := http.NewServeMux()
-	mux.HandleFunc("/", ppic.Handler)
+	config := ppic.HandlerConfig{
+		Style:         *style,
+		EncodeOptions: encodeOpts,
+		EncodeLimits:  encodeLimits,
+	}
+
+	mux.Handle("/", ppic.NewHandler(config))
+
+	// Serve images rendered from grids in the query if a path is set (with any of the encoder extensions). This path
+	// can't be used as a key, so it's disabled by default.
+	if len(*renderPath) > 0 {
+		render := ppic.NewRenderHandler(config)
+		mux.Handle(*renderPath, render)
+
+		for _, e := range ppic.Encoders() {
+			for _, ext := range e.Extensions {
+				mux.Handle(*renderPath+ext, render)
+			}
+		}
+	}
 
 	// Enable pprof debug routes if the debug flag is set.
 	if *debug {
@@ -0,0 +1,333 @@
+package ppic
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// mixColors mixes two colors together, with t specifying how much of b to use (0 is all a, 1 is all b).
+func mixColors(a, b color.Color, t float64) color.NRGBA {
+	na := toNRGBA(a)
+	nb := toNRGBA(b)
+
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x)*(1-t) + float64(y)*t))
+	}
+
+	return color.NRGBA{
+		R: mix(na.R, nb.R),
+		G: mix(na.G, nb.G),
+		B: mix(na.B, nb.B),
+		A: mix(na.A, nb.A),
+	}
+}
+
+// hueToRGB is a helper for hslToRGB, as described in https://www.w3.org/TR/css-color-3/#hsl-color.
+func hueToRGB(m1, m2, h float64) float64 {
+	if h < 0 {
+		h++
+	}
+
+	if h > 1 {
+		h--
+	}
+
+	switch {
+	case h*6 < 1:
+		return m1 + (m2-m1)*h*6
+	case h*2 < 1:
+		return m2
+	case h*3 < 2:
+		return m1 + (m2-m1)*(2.0/3-h)*6
+	default:
+		return m1
+	}
+}
+
+// hslToRGB converts a color from HSL to RGB, with the hue in degrees and the saturation and lightness between 0 and 1.
+//
+// Saturation and lightness values outside of the range are clamped, and the hue wraps around.
+func hslToRGB(h, s, l float64) color.RGBA {
+	h = math.Mod(h, 360)
+
+	if h < 0 {
+		h += 360
+	}
+
+	h /= 360
+	s = math.Max(0, math.Min(1, s))
+	l = math.Max(0, math.Min(1, l))
+
+	var m2 float64
+
+	if l <= 0.5 {
+		m2 = l * (s + 1)
+	} else {
+		m2 = l + s - l*s
+	}
+
+	m1 := l*2 - m2
+
+	return color.RGBA{
+		R: uint8(math.Round(hueToRGB(m1, m2, h+1.0/3) * 0xFF)),
+		G: uint8(math.Round(hueToRGB(m1, m2, h) * 0xFF)),
+		B: uint8(math.Round(hueToRGB(m1, m2, h-1.0/3) * 0xFF)),
+		A: 0xFF,
+	}
+}
+
+// rgbToHSL converts a color to HSL, with the hue in degrees and the saturation and lightness between 0 and 1.
+func rgbToHSL(c color.Color) (h, s, l float64) {
+	n := toNRGBA(c)
+	r, g, b := float64(n.R)/0xFF, float64(n.G)/0xFF, float64(n.B)/0xFF
+
+	hi := math.Max(r, math.Max(g, b))
+	lo := math.Min(r, math.Min(g, b))
+	l = (hi + lo) / 2
+
+	// Grays don't have a hue or saturation.
+	if hi == lo {
+		return 0, 0, l
+	}
+
+	d := hi - lo
+
+	if l > 0.5 {
+		s = d / (2 - hi - lo)
+	} else {
+		s = d / (hi + lo)
+	}
+
+	switch hi {
+	case r:
+		h = (g - b) / d
+
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+
+	return h * 60, s, l
+}
+
+// linearize converts an sRGB color component into linear space.
+func linearize(v uint8) float64 {
+	f := float64(v) / 0xFF
+
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+// relativeLuminance returns the relative luminance of a color, as described in
+// https://www.w3.org/TR/WCAG20/#relativeluminancedef.
+func relativeLuminance(c color.Color) float64 {
+	n := toNRGBA(c)
+
+	return 0.2126*linearize(n.R) + 0.7152*linearize(n.G) + 0.0722*linearize(n.B)
+}
+
+// ErrInvalidColor is an error caused by parsing a color which isn't in a supported format.
+var ErrInvalidColor = errors.New("invalid color")
+
+// ParseColor parses a color written in one of the following formats (ignoring case);
+//
+//   - hex digits (#rgb, #rrggbb or #rrggbbaa, with the '#' being optional)
+//   - CSS named colors (such as "red" or "transparent")
+//   - rgb() and rgba() (such as "rgb(255, 0, 0)", "rgb(100% 0% 0% / 50%)" or "rgba(255, 0, 0, 0.5)")
+//   - hsl() and hsla() (such as "hsl(0, 100%, 50%)", "hsl(0.5turn 100% 50% / 0.5)" or "hsla(0, 100%, 50%, 0.5)")
+//
+// Values outside of their range are clamped, in the same way as CSS.
+func ParseColor(s string) (color.Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if c, ok := colorNames[s]; ok {
+		return c, nil
+	}
+
+	if i := strings.IndexByte(s, '('); i > 0 && strings.HasSuffix(s, ")") {
+		args, err := colorArgs(s[i+1 : len(s)-1])
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch s[:i] {
+		case "rgb", "rgba":
+			return parseRGBColor(args)
+		case "hsl", "hsla":
+			return parseHSLColor(args)
+		default:
+			return nil, ErrInvalidColor
+		}
+	}
+
+	return parseHexColor(s)
+}
+
+// colorArgs splits the arguments of a color function, which are either separated by commas or by spaces (with the
+// alpha being separated by a slash).
+func colorArgs(s string) ([]string, error) {
+	var args []string
+
+	if strings.Contains(s, ",") {
+		args = strings.Split(s, ",")
+
+		for i, arg := range args {
+			args[i] = strings.TrimSpace(arg)
+		}
+	} else {
+		alpha := ""
+
+		if i := strings.IndexByte(s, '/'); i >= 0 {
+			alpha = strings.TrimSpace(s[i+1:])
+			s = s[:i]
+
+			if len(alpha) == 0 {
+				return nil, ErrInvalidColor
+			}
+		}
+
+		args = strings.Fields(s)
+
+		if len(alpha) > 0 {
+			args = append(args, alpha)
+		}
+	}
+
+	if len(args) != 3 && len(args) != 4 {
+		return nil, ErrInvalidColor
+	}
+
+	return args, nil
+}
+
+// parseColorNumber parses a number from a color function, returning it divided by max if it isn't a percentage (so that
+// the result is between 0 and 1 when it's in range).
+func parseColorNumber(s string, max float64) (float64, error) {
+	if strings.HasSuffix(s, "%") {
+		s = strings.TrimSuffix(s, "%")
+		max = 100
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, ErrInvalidColor
+	}
+
+	return math.Max(0, math.Min(1, v/max)), nil
+}
+
+// parseColorAlpha parses the alpha of a color function (which is opaque if there isn't one) and returns the color with
+// it applied.
+func parseColorAlpha(c color.RGBA, args []string) (color.Color, error) {
+	a := 1.0
+
+	if len(args) == 4 {
+		var err error
+
+		if a, err = parseColorNumber(args[3], 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: uint8(math.Round(a * 0xFF))}, nil
+}
+
+// parseRGBColor parses the arguments of rgb() or rgba().
+func parseRGBColor(args []string) (color.Color, error) {
+	var c [3]uint8
+
+	for i := range c {
+		v, err := parseColorNumber(args[i], 0xFF)
+
+		if err != nil {
+			return nil, err
+		}
+
+		c[i] = uint8(math.Round(v * 0xFF))
+	}
+
+	return parseColorAlpha(color.RGBA{R: c[0], G: c[1], B: c[2], A: 0xFF}, args)
+}
+
+// hueUnits contains the units that a hue can be specified in, along with the number of degrees in each unit ("grad"
+// needs to come before "rad" as they share a suffix).
+var hueUnits = []struct {
+	suffix  string
+	degrees float64
+}{
+	{"deg", 1},
+	{"grad", 0.9},
+	{"rad", 180 / math.Pi},
+	{"turn", 360},
+}
+
+// parseHSLColor parses the arguments of hsl() or hsla().
+func parseHSLColor(args []string) (color.Color, error) {
+	h, unit := args[0], 1.0
+
+	for _, u := range hueUnits {
+		if strings.HasSuffix(h, u.suffix) {
+			h, unit = strings.TrimSuffix(h, u.suffix), u.degrees
+
+			break
+		}
+	}
+
+	hue, err := strconv.ParseFloat(h, 64)
+
+	if err != nil || math.IsNaN(hue) || math.IsInf(hue, 0) {
+		return nil, ErrInvalidColor
+	}
+
+	s, err := parseColorNumber(args[1], 100)
+
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := parseColorNumber(args[2], 100)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseColorAlpha(hslToRGB(hue*unit, s, l), args)
+}
+
+// parseHexColor parses a color written as hex digits (#rgb, #rrggbb or #rrggbbaa, with the '#' being optional).
+func parseHexColor(s string) (color.Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+
+	// Expand the short form so that each channel has two digits.
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	if len(hex) != 8 {
+		return nil, ErrInvalidColor
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+
+	if err != nil {
+		return nil, ErrInvalidColor
+	}
+
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
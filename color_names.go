@@ -0,0 +1,156 @@
+package ppic
+
+import "image/color"
+
+// colorNames contains the CSS named colors (https://www.w3.org/TR/css-color-4/#named-colors).
+var colorNames = map[string]color.NRGBA{
+	"aliceblue":            {R: 0xF0, G: 0xF8, B: 0xFF, A: 0xFF},
+	"antiquewhite":         {R: 0xFA, G: 0xEB, B: 0xD7, A: 0xFF},
+	"aqua":                 {R: 0x00, G: 0xFF, B: 0xFF, A: 0xFF},
+	"aquamarine":           {R: 0x7F, G: 0xFF, B: 0xD4, A: 0xFF},
+	"azure":                {R: 0xF0, G: 0xFF, B: 0xFF, A: 0xFF},
+	"beige":                {R: 0xF5, G: 0xF5, B: 0xDC, A: 0xFF},
+	"bisque":               {R: 0xFF, G: 0xE4, B: 0xC4, A: 0xFF},
+	"black":                {R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+	"blanchedalmond":       {R: 0xFF, G: 0xEB, B: 0xCD, A: 0xFF},
+	"blue":                 {R: 0x00, G: 0x00, B: 0xFF, A: 0xFF},
+	"blueviolet":           {R: 0x8A, G: 0x2B, B: 0xE2, A: 0xFF},
+	"brown":                {R: 0xA5, G: 0x2A, B: 0x2A, A: 0xFF},
+	"burlywood":            {R: 0xDE, G: 0xB8, B: 0x87, A: 0xFF},
+	"cadetblue":            {R: 0x5F, G: 0x9E, B: 0xA0, A: 0xFF},
+	"chartreuse":           {R: 0x7F, G: 0xFF, B: 0x00, A: 0xFF},
+	"chocolate":            {R: 0xD2, G: 0x69, B: 0x1E, A: 0xFF},
+	"coral":                {R: 0xFF, G: 0x7F, B: 0x50, A: 0xFF},
+	"cornflowerblue":       {R: 0x64, G: 0x95, B: 0xED, A: 0xFF},
+	"cornsilk":             {R: 0xFF, G: 0xF8, B: 0xDC, A: 0xFF},
+	"crimson":              {R: 0xDC, G: 0x14, B: 0x3C, A: 0xFF},
+	"cyan":                 {R: 0x00, G: 0xFF, B: 0xFF, A: 0xFF},
+	"darkblue":             {R: 0x00, G: 0x00, B: 0x8B, A: 0xFF},
+	"darkcyan":             {R: 0x00, G: 0x8B, B: 0x8B, A: 0xFF},
+	"darkgoldenrod":        {R: 0xB8, G: 0x86, B: 0x0B, A: 0xFF},
+	"darkgray":             {R: 0xA9, G: 0xA9, B: 0xA9, A: 0xFF},
+	"darkgreen":            {R: 0x00, G: 0x64, B: 0x00, A: 0xFF},
+	"darkgrey":             {R: 0xA9, G: 0xA9, B: 0xA9, A: 0xFF},
+	"darkkhaki":            {R: 0xBD, G: 0xB7, B: 0x6B, A: 0xFF},
+	"darkmagenta":          {R: 0x8B, G: 0x00, B: 0x8B, A: 0xFF},
+	"darkolivegreen":       {R: 0x55, G: 0x6B, B: 0x2F, A: 0xFF},
+	"darkorange":           {R: 0xFF, G: 0x8C, B: 0x00, A: 0xFF},
+	"darkorchid":           {R: 0x99, G: 0x32, B: 0xCC, A: 0xFF},
+	"darkred":              {R: 0x8B, G: 0x00, B: 0x00, A: 0xFF},
+	"darksalmon":           {R: 0xE9, G: 0x96, B: 0x7A, A: 0xFF},
+	"darkseagreen":         {R: 0x8F, G: 0xBC, B: 0x8F, A: 0xFF},
+	"darkslateblue":        {R: 0x48, G: 0x3D, B: 0x8B, A: 0xFF},
+	"darkslategray":        {R: 0x2F, G: 0x4F, B: 0x4F, A: 0xFF},
+	"darkslategrey":        {R: 0x2F, G: 0x4F, B: 0x4F, A: 0xFF},
+	"darkturquoise":        {R: 0x00, G: 0xCE, B: 0xD1, A: 0xFF},
+	"darkviolet":           {R: 0x94, G: 0x00, B: 0xD3, A: 0xFF},
+	"deeppink":             {R: 0xFF, G: 0x14, B: 0x93, A: 0xFF},
+	"deepskyblue":          {R: 0x00, G: 0xBF, B: 0xFF, A: 0xFF},
+	"dimgray":              {R: 0x69, G: 0x69, B: 0x69, A: 0xFF},
+	"dimgrey":              {R: 0x69, G: 0x69, B: 0x69, A: 0xFF},
+	"dodgerblue":           {R: 0x1E, G: 0x90, B: 0xFF, A: 0xFF},
+	"firebrick":            {R: 0xB2, G: 0x22, B: 0x22, A: 0xFF},
+	"floralwhite":          {R: 0xFF, G: 0xFA, B: 0xF0, A: 0xFF},
+	"forestgreen":          {R: 0x22, G: 0x8B, B: 0x22, A: 0xFF},
+	"fuchsia":              {R: 0xFF, G: 0x00, B: 0xFF, A: 0xFF},
+	"gainsboro":            {R: 0xDC, G: 0xDC, B: 0xDC, A: 0xFF},
+	"ghostwhite":           {R: 0xF8, G: 0xF8, B: 0xFF, A: 0xFF},
+	"gold":                 {R: 0xFF, G: 0xD7, B: 0x00, A: 0xFF},
+	"goldenrod":            {R: 0xDA, G: 0xA5, B: 0x20, A: 0xFF},
+	"gray":                 {R: 0x80, G: 0x80, B: 0x80, A: 0xFF},
+	"green":                {R: 0x00, G: 0x80, B: 0x00, A: 0xFF},
+	"greenyellow":          {R: 0xAD, G: 0xFF, B: 0x2F, A: 0xFF},
+	"grey":                 {R: 0x80, G: 0x80, B: 0x80, A: 0xFF},
+	"honeydew":             {R: 0xF0, G: 0xFF, B: 0xF0, A: 0xFF},
+	"hotpink":              {R: 0xFF, G: 0x69, B: 0xB4, A: 0xFF},
+	"indianred":            {R: 0xCD, G: 0x5C, B: 0x5C, A: 0xFF},
+	"indigo":               {R: 0x4B, G: 0x00, B: 0x82, A: 0xFF},
+	"ivory":                {R: 0xFF, G: 0xFF, B: 0xF0, A: 0xFF},
+	"khaki":                {R: 0xF0, G: 0xE6, B: 0x8C, A: 0xFF},
+	"lavender":             {R: 0xE6, G: 0xE6, B: 0xFA, A: 0xFF},
+	"lavenderblush":        {R: 0xFF, G: 0xF0, B: 0xF5, A: 0xFF},
+	"lawngreen":            {R: 0x7C, G: 0xFC, B: 0x00, A: 0xFF},
+	"lemonchiffon":         {R: 0xFF, G: 0xFA, B: 0xCD, A: 0xFF},
+	"lightblue":            {R: 0xAD, G: 0xD8, B: 0xE6, A: 0xFF},
+	"lightcoral":           {R: 0xF0, G: 0x80, B: 0x80, A: 0xFF},
+	"lightcyan":            {R: 0xE0, G: 0xFF, B: 0xFF, A: 0xFF},
+	"lightgoldenrodyellow": {R: 0xFA, G: 0xFA, B: 0xD2, A: 0xFF},
+	"lightgray":            {R: 0xD3, G: 0xD3, B: 0xD3, A: 0xFF},
+	"lightgreen":           {R: 0x90, G: 0xEE, B: 0x90, A: 0xFF},
+	"lightgrey":            {R: 0xD3, G: 0xD3, B: 0xD3, A: 0xFF},
+	"lightpink":            {R: 0xFF, G: 0xB6, B: 0xC1, A: 0xFF},
+	"lightsalmon":          {R: 0xFF, G: 0xA0, B: 0x7A, A: 0xFF},
+	"lightseagreen":        {R: 0x20, G: 0xB2, B: 0xAA, A: 0xFF},
+	"lightskyblue":         {R: 0x87, G: 0xCE, B: 0xFA, A: 0xFF},
+	"lightslategray":       {R: 0x77, G: 0x88, B: 0x99, A: 0xFF},
+	"lightslategrey":       {R: 0x77, G: 0x88, B: 0x99, A: 0xFF},
+	"lightsteelblue":       {R: 0xB0, G: 0xC4, B: 0xDE, A: 0xFF},
+	"lightyellow":          {R: 0xFF, G: 0xFF, B: 0xE0, A: 0xFF},
+	"lime":                 {R: 0x00, G: 0xFF, B: 0x00, A: 0xFF},
+	"limegreen":            {R: 0x32, G: 0xCD, B: 0x32, A: 0xFF},
+	"linen":                {R: 0xFA, G: 0xF0, B: 0xE6, A: 0xFF},
+	"magenta":              {R: 0xFF, G: 0x00, B: 0xFF, A: 0xFF},
+	"maroon":               {R: 0x80, G: 0x00, B: 0x00, A: 0xFF},
+	"mediumaquamarine":     {R: 0x66, G: 0xCD, B: 0xAA, A: 0xFF},
+	"mediumblue":           {R: 0x00, G: 0x00, B: 0xCD, A: 0xFF},
+	"mediumorchid":         {R: 0xBA, G: 0x55, B: 0xD3, A: 0xFF},
+	"mediumpurple":         {R: 0x93, G: 0x70, B: 0xDB, A: 0xFF},
+	"mediumseagreen":       {R: 0x3C, G: 0xB3, B: 0x71, A: 0xFF},
+	"mediumslateblue":      {R: 0x7B, G: 0x68, B: 0xEE, A: 0xFF},
+	"mediumspringgreen":    {R: 0x00, G: 0xFA, B: 0x9A, A: 0xFF},
+	"mediumturquoise":      {R: 0x48, G: 0xD1, B: 0xCC, A: 0xFF},
+	"mediumvioletred":      {R: 0xC7, G: 0x15, B: 0x85, A: 0xFF},
+	"midnightblue":         {R: 0x19, G: 0x19, B: 0x70, A: 0xFF},
+	"mintcream":            {R: 0xF5, G: 0xFF, B: 0xFA, A: 0xFF},
+	"mistyrose":            {R: 0xFF, G: 0xE4, B: 0xE1, A: 0xFF},
+	"moccasin":             {R: 0xFF, G: 0xE4, B: 0xB5, A: 0xFF},
+	"navajowhite":          {R: 0xFF, G: 0xDE, B: 0xAD, A: 0xFF},
+	"navy":                 {R: 0x00, G: 0x00, B: 0x80, A: 0xFF},
+	"oldlace":              {R: 0xFD, G: 0xF5, B: 0xE6, A: 0xFF},
+	"olive":                {R: 0x80, G: 0x80, B: 0x00, A: 0xFF},
+	"olivedrab":            {R: 0x6B, G: 0x8E, B: 0x23, A: 0xFF},
+	"orange":               {R: 0xFF, G: 0xA5, B: 0x00, A: 0xFF},
+	"orangered":            {R: 0xFF, G: 0x45, B: 0x00, A: 0xFF},
+	"orchid":               {R: 0xDA, G: 0x70, B: 0xD6, A: 0xFF},
+	"palegoldenrod":        {R: 0xEE, G: 0xE8, B: 0xAA, A: 0xFF},
+	"palegreen":            {R: 0x98, G: 0xFB, B: 0x98, A: 0xFF},
+	"paleturquoise":        {R: 0xAF, G: 0xEE, B: 0xEE, A: 0xFF},
+	"palevioletred":        {R: 0xDB, G: 0x70, B: 0x93, A: 0xFF},
+	"papayawhip":           {R: 0xFF, G: 0xEF, B: 0xD5, A: 0xFF},
+	"peachpuff":            {R: 0xFF, G: 0xDA, B: 0xB9, A: 0xFF},
+	"peru":                 {R: 0xCD, G: 0x85, B: 0x3F, A: 0xFF},
+	"pink":                 {R: 0xFF, G: 0xC0, B: 0xCB, A: 0xFF},
+	"plum":                 {R: 0xDD, G: 0xA0, B: 0xDD, A: 0xFF},
+	"powderblue":           {R: 0xB0, G: 0xE0, B: 0xE6, A: 0xFF},
+	"purple":               {R: 0x80, G: 0x00, B: 0x80, A: 0xFF},
+	"rebeccapurple":        {R: 0x66, G: 0x33, B: 0x99, A: 0xFF},
+	"red":                  {R: 0xFF, G: 0x00, B: 0x00, A: 0xFF},
+	"rosybrown":            {R: 0xBC, G: 0x8F, B: 0x8F, A: 0xFF},
+	"royalblue":            {R: 0x41, G: 0x69, B: 0xE1, A: 0xFF},
+	"saddlebrown":          {R: 0x8B, G: 0x45, B: 0x13, A: 0xFF},
+	"salmon":               {R: 0xFA, G: 0x80, B: 0x72, A: 0xFF},
+	"sandybrown":           {R: 0xF4, G: 0xA4, B: 0x60, A: 0xFF},
+	"seagreen":             {R: 0x2E, G: 0x8B, B: 0x57, A: 0xFF},
+	"seashell":             {R: 0xFF, G: 0xF5, B: 0xEE, A: 0xFF},
+	"sienna":               {R: 0xA0, G: 0x52, B: 0x2D, A: 0xFF},
+	"silver":               {R: 0xC0, G: 0xC0, B: 0xC0, A: 0xFF},
+	"skyblue":              {R: 0x87, G: 0xCE, B: 0xEB, A: 0xFF},
+	"slateblue":            {R: 0x6A, G: 0x5A, B: 0xCD, A: 0xFF},
+	"slategray":            {R: 0x70, G: 0x80, B: 0x90, A: 0xFF},
+	"slategrey":            {R: 0x70, G: 0x80, B: 0x90, A: 0xFF},
+	"snow":                 {R: 0xFF, G: 0xFA, B: 0xFA, A: 0xFF},
+	"springgreen":          {R: 0x00, G: 0xFF, B: 0x7F, A: 0xFF},
+	"steelblue":            {R: 0x46, G: 0x82, B: 0xB4, A: 0xFF},
+	"tan":                  {R: 0xD2, G: 0xB4, B: 0x8C, A: 0xFF},
+	"teal":                 {R: 0x00, G: 0x80, B: 0x80, A: 0xFF},
+	"thistle":              {R: 0xD8, G: 0xBF, B: 0xD8, A: 0xFF},
+	"tomato":               {R: 0xFF, G: 0x63, B: 0x47, A: 0xFF},
+	"turquoise":            {R: 0x40, G: 0xE0, B: 0xD0, A: 0xFF},
+	"violet":               {R: 0xEE, G: 0x82, B: 0xEE, A: 0xFF},
+	"wheat":                {R: 0xF5, G: 0xDE, B: 0xB3, A: 0xFF},
+	"white":                {R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+	"whitesmoke":           {R: 0xF5, G: 0xF5, B: 0xF5, A: 0xFF},
+	"yellow":               {R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF},
+	"yellowgreen":          {R: 0x9A, G: 0xCD, B: 0x32, A: 0xFF},
+	"transparent":          {},
+}
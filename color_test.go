@@ -0,0 +1,66 @@
+package ppic_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+func TestParseColor(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected color.NRGBA
+	}{
+		{"#f00", color.NRGBA{R: 0xFF, A: 0xFF}},
+		{"F00", color.NRGBA{R: 0xFF, A: 0xFF}},
+		{"#12ab34", color.NRGBA{R: 0x12, G: 0xAB, B: 0x34, A: 0xFF}},
+		{"#12ab3480", color.NRGBA{R: 0x12, G: 0xAB, B: 0x34, A: 0x80}},
+		{"red", color.NRGBA{R: 0xFF, A: 0xFF}},
+		{" RebeccaPurple ", color.NRGBA{R: 0x66, G: 0x33, B: 0x99, A: 0xFF}},
+		{"transparent", color.NRGBA{}},
+		{"rgb(255, 0, 0)", color.NRGBA{R: 0xFF, A: 0xFF}},
+		{"rgb(300, -10, 0)", color.NRGBA{R: 0xFF, A: 0xFF}},
+		{"rgba(255, 0, 0, 0.5)", color.NRGBA{R: 0xFF, A: 0x80}},
+		{"rgb(100% 0% 50%)", color.NRGBA{R: 0xFF, B: 0x80, A: 0xFF}},
+		{"rgb(255 0 0 / 50%)", color.NRGBA{R: 0xFF, A: 0x80}},
+		{"hsl(0, 100%, 50%)", color.NRGBA{R: 0xFF, A: 0xFF}},
+		{"hsl(120deg 100% 25%)", color.NRGBA{G: 0x80, A: 0xFF}},
+		{"hsl(0.5turn 100% 50% / 0.5)", color.NRGBA{G: 0xFF, B: 0xFF, A: 0x80}},
+		{"hsl(400grad 100% 50%)", color.NRGBA{R: 0xFF, A: 0xFF}},
+		{"hsla(240, 100%, 50%, 1)", color.NRGBA{B: 0xFF, A: 0xFF}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.text, func(t *testing.T) {
+			actual, err := ppic.ParseColor(c.text)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if n := color.NRGBAModel.Convert(actual).(color.NRGBA); n != c.expected {
+				t.Errorf("expected %v but got %v", c.expected, n)
+			}
+		})
+	}
+}
+
+func TestParseColorWithInvalidColor(t *testing.T) {
+	invalid := []string{
+		"", "#", "#ff", "#ffff", "#fffff", "#ggg", "0x123456", "notacolor", "rgb()", "rgb(1, 2)", "rgb(1, 2, 3, 4, 5)",
+		"rgb(a, b, c)", "rgb(1 2 3 /)", "hsl(foo, 1%, 1%)", "hsl(0, 1%, NaN)", "cmyk(1, 2, 3)", "rgb(1, 2, 3",
+	}
+
+	for _, text := range invalid {
+		text := text
+
+		t.Run(text, func(t *testing.T) {
+			if c, err := ppic.ParseColor(text); err != ppic.ErrInvalidColor {
+				t.Errorf("expected error to be %q but got %v (%v)", ppic.ErrInvalidColor, err, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package ppic
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"strings"
+)
+
+// ErrUnsupportedFormat is an error caused by specifying a format which cannot be encoded.
+var ErrUnsupportedFormat = errors.New("unsupported file format")
+
+// DataURI returns a data URI containing the provided data.
+//
+// Any whitespace around the parameters of the content type is removed (so "text/css; charset=utf-8" becomes
+// "text/css;charset=utf-8"), as RFC 2397 doesn't allow whitespace in the media type.
+func DataURI(contentType string, data []byte) string {
+	parts := strings.Split(contentType, ";")
+
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+
+	return "data:" + strings.Join(parts, ";") + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// EncodeDataURI encodes an image in the specified format (any registered encoder's extension, such as "png" or "jpg")
+// and returns it as a data URI.
+func EncodeDataURI(img image.Image, format string) (string, error) {
+	encoder, ok := LookupEncoder(format)
+
+	if !ok {
+		return "", ErrUnsupportedFormat
+	}
+
+	buf := bytes.Buffer{}
+
+	if err := encoder.Encode(&buf, img, nil); err != nil {
+		return "", err
+	}
+
+	return DataURI(encoder.MIMEType, buf.Bytes()), nil
+}
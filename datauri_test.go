@@ -0,0 +1,95 @@
+package ppic_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+	"github.com/jackwilsdon/go-ppic/ppictest"
+)
+
+func TestDataURI(t *testing.T) {
+	cases := []struct {
+		contentType string
+		uri         string
+	}{
+		{"text/plain", "data:text/plain;base64,aGVsbG8="},
+		{"text/css; charset=utf-8", "data:text/css;charset=utf-8;base64,aGVsbG8="},
+		{" text/html ;  charset=utf-8 ", "data:text/html;charset=utf-8;base64,aGVsbG8="},
+	}
+
+	for _, c := range cases {
+		if uri := ppic.DataURI(c.contentType, []byte("hello")); uri != c.uri {
+			t.Errorf("expected data URI for %q to be %q but got %q", c.contentType, c.uri, uri)
+		}
+	}
+}
+
+func TestEncodeDataURI(t *testing.T) {
+	expected := [8]string{
+		"# #  # #",
+		"# #### #",
+		"        ",
+		"# #  # #",
+		"  #  #  ",
+		"        ",
+		"##    ##",
+		"#      #",
+	}
+
+	img, err := ppic.GenerateImage(ppictest.Parse(expected), 64, ppic.DefaultPalette)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		format string
+		prefix string
+	}{
+		{"png", "data:image/png;base64,"},
+		{"gif", "data:image/gif;base64,"},
+		{"jpg", "data:image/jpeg;base64,"},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.format, func(t *testing.T) {
+			uri, err := ppic.EncodeDataURI(img, c.format)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(uri, c.prefix) {
+				t.Fatalf("expected data URI to start with %q but got %q", c.prefix, uri)
+			}
+
+			data, err := base64.StdEncoding.DecodeString(uri[len(c.prefix):])
+
+			if err != nil {
+				t.Fatalf("failed to decode data URI: %s", err)
+			}
+
+			if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+				t.Errorf("failed to parse image: %s", err)
+			}
+		})
+	}
+}
+
+func TestEncodeDataURIWithUnsupportedFormat(t *testing.T) {
+	img, err := ppic.GenerateImage(ppic.Generate("jackwilsdon", true, false), 64, ppic.DefaultPalette)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ppic.EncodeDataURI(img, "bmp"); err != ppic.ErrUnsupportedFormat {
+		t.Errorf("expected error to be %q but got %v", ppic.ErrUnsupportedFormat, err)
+	}
+}
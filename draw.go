@@ -0,0 +1,55 @@
+package ppic
+
+import (
+	"image"
+	"image/draw"
+)
+
+// DrawOptions configures how Draw composites a grid onto an image.
+type DrawOptions struct {
+	// Mask is an optional mask which the grid is drawn through, with MaskPoint lining up with the top left of the
+	// target rectangle (in the same way as draw.DrawMask).
+	Mask      image.Image
+	MaskPoint image.Point
+
+	// Op is the compositing operator, which defaults to draw.Over.
+	Op draw.Op
+}
+
+// Draw draws a grid directly onto an image, scaling it to fill the target rectangle.
+//
+// The rectangle can be any size, with the grid cells being as even as possible if it isn't a multiple of 8. opts can
+// be nil to draw over the image without a mask.
+func Draw(dst draw.Image, r image.Rectangle, grid Grid, p Palette, opts *DrawOptions) {
+	if opts == nil {
+		opts = &DrawOptions{}
+	}
+
+	colors := [2]image.Image{image.NewUniform(p.Background), image.NewUniform(p.Foreground)}
+	w, h := r.Dx(), r.Dy()
+
+	for y, row := range grid {
+		y0 := r.Min.Y + y*h/8
+		y1 := r.Min.Y + (y+1)*h/8
+
+		// Draw runs of cells with the same value together.
+		for x := 0; x < 8; {
+			end := x + 1
+
+			for end < 8 && row[end] == row[x] {
+				end++
+			}
+
+			cell := image.Rect(r.Min.X+x*w/8, y0, r.Min.X+end*w/8, y1)
+			src := colors[0]
+
+			if row[x] {
+				src = colors[1]
+			}
+
+			draw.DrawMask(dst, cell, src, image.Point{}, opts.Mask, opts.MaskPoint.Add(cell.Min.Sub(r.Min)), opts.Op)
+
+			x = end
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package ppic_test
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+	"github.com/jackwilsdon/go-ppic/ppictest"
+)
+
+var drawGrid = [8]string{
+	"# #  # #",
+	"# #### #",
+	"        ",
+	"# #  # #",
+	"  #  #  ",
+	"        ",
+	"##    ##",
+	"#      #",
+}
+
+func BenchmarkDraw(b *testing.B) {
+	grid := ppictest.Parse(drawGrid)
+	dst := image.NewRGBA(image.Rect(0, 0, 1024, 1024))
+	r := image.Rect(256, 256, 768, 768)
+
+	b.Run("GenerateImage", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for n := 0; n < b.N; n++ {
+			img, err := ppic.GenerateImage(grid, r.Dx(), ppic.DefaultPalette)
+
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			draw.Draw(dst, r, img, image.Point{}, draw.Over)
+		}
+	})
+
+	b.Run("Draw", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for n := 0; n < b.N; n++ {
+			ppic.Draw(dst, r, grid, ppic.DefaultPalette, nil)
+		}
+	})
+}
+
+func TestDraw(t *testing.T) {
+	grid := ppictest.Parse(drawGrid)
+	p := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.Black}
+	dst := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	r := image.Rect(10, 20, 74, 84)
+
+	ppic.Draw(dst, r, grid, p, nil)
+
+	// CompareImage expects images to start at (0, 0), so the rectangle needs to be moved there first.
+	moved := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(moved, moved.Rect, dst, r.Min, draw.Src)
+
+	if err := ppictest.CompareImage(moved, p, drawGrid); err != nil {
+		t.Error(err)
+	}
+
+	// Pixels outside of the rectangle shouldn't be touched.
+	for _, pt := range []image.Point{{9, 20}, {10, 19}, {74, 20}, {10, 84}} {
+		if c := dst.RGBAAt(pt.X, pt.Y); c != (color.RGBA{}) {
+			t.Errorf("expected pixel at %v to be untouched but got %v", pt, c)
+		}
+	}
+}
+
+func TestDrawUneven(t *testing.T) {
+	grid := ppictest.Parse(drawGrid)
+	fg, bg := color.RGBA{R: 0xFF, A: 0xFF}, color.RGBA{B: 0xFF, A: 0xFF}
+	p := ppic.Palette{Foreground: fg, Background: bg}
+	dst := image.NewRGBA(image.Rect(0, 0, 21, 13))
+
+	ppic.Draw(dst, dst.Rect, grid, p, nil)
+
+	// Every pixel should be drawn using one of the colors.
+	for y := 0; y < 13; y++ {
+		for x := 0; x < 21; x++ {
+			c := dst.RGBAAt(x, y)
+
+			if c != fg && c != bg {
+				t.Fatalf("expected pixel at (%d, %d) to be drawn but got %v", x, y, c)
+			}
+		}
+	}
+}
+
+func TestDrawOptions(t *testing.T) {
+	grid := ppictest.Parse(drawGrid)
+	p := ppic.Palette{Foreground: color.RGBA{R: 0x80, A: 0x80}, Background: color.Transparent}
+	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+
+	cases := []struct {
+		name     string
+		opts     *ppic.DrawOptions
+		expected color.RGBA
+	}{
+		{"Over", nil, color.RGBA{R: 0xFF, G: 0x7F, B: 0x7F, A: 0xFF}},
+		{"Src", &ppic.DrawOptions{Op: draw.Src}, color.RGBA{R: 0x80, A: 0x80}},
+		{"Mask", &ppic.DrawOptions{Mask: image.NewUniform(color.Transparent)}, white},
+		{"MaskSrc", &ppic.DrawOptions{Mask: image.NewUniform(color.Transparent), Op: draw.Src}, color.RGBA{}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			dst := image.NewRGBA(image.Rect(0, 0, 16, 16))
+			draw.Draw(dst, dst.Rect, image.NewUniform(white), image.Point{}, draw.Src)
+
+			ppic.Draw(dst, dst.Rect, grid, p, c.opts)
+
+			// The top left cell is the foreground.
+			if actual := dst.RGBAAt(0, 0); actual != c.expected {
+				t.Errorf("expected %v but got %v", c.expected, actual)
+			}
+		})
+	}
+
+	// Masks line up with the top left of the rectangle.
+	mask := image.NewAlpha(image.Rect(0, 0, 16, 16))
+	mask.SetAlpha(1, 1, color.Alpha{A: 0xFF})
+
+	dst := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	ppic.Draw(dst, image.Rect(16, 16, 32, 32), grid, ppic.DefaultPalette, &ppic.DrawOptions{Mask: mask, Op: draw.Src})
+
+	for _, pt := range []image.Point{{16, 16}, {17, 17}} {
+		expected := color.RGBA{}
+
+		if pt == (image.Point{X: 17, Y: 17}) {
+			expected = color.RGBA{A: 0xFF}
+		}
+
+		if actual := dst.RGBAAt(pt.X, pt.Y); actual != expected {
+			t.Errorf("expected %v at %v but got %v", expected, pt, actual)
+		}
+	}
+}
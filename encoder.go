@@ -0,0 +1,403 @@
+package ppic
+
+import (
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EncodeOptions are format specific options passed to an encoder (such as "quality" for JPEG images).
+//
+// Encoders ignore any options which they don't recognise.
+type EncodeOptions map[string]string
+
+// Encoder encodes images in a specific format.
+type Encoder struct {
+	// Name is the name of the format (such as "png").
+	Name string
+
+	// MIMEType is the MIME type of the encoded images (such as "image/png").
+	MIMEType string
+
+	// Extensions are the file extensions used for the format, including the leading dot (such as ".png").
+	Extensions []string
+
+	// Options are the names of the options accepted by Encode, which Handler reads from the query parameters.
+	Options []string
+
+	// Encode writes the image to w in the format, configured using the options.
+	Encode func(w io.Writer, img image.Image, opts EncodeOptions) error
+}
+
+// EncodeOptionError is returned by encoders when an option has an invalid value.
+type EncodeOptionError struct {
+	Option string
+	Value  string
+}
+
+// Error returns a description of the invalid option.
+func (e *EncodeOptionError) Error() string {
+	return fmt.Sprintf("invalid %s %q", e.Option, e.Value)
+}
+
+// EncodeLimit restricts the values of a numeric encoder option (such as "quality" for JPEG images).
+type EncodeLimit struct {
+	Min int
+	Max int
+}
+
+// Check returns an error if an option's value is outside of the limit, or isn't a number.
+func (l EncodeLimit) Check(name, value string) error {
+	i, err := strconv.Atoi(value)
+
+	if err != nil {
+		return &EncodeOptionError{Option: name, Value: value}
+	}
+
+	if i < l.Min || i > l.Max {
+		return fmt.Errorf("%s must be between %d and %d", name, l.Min, l.Max)
+	}
+
+	return nil
+}
+
+// encoderRegistry contains the registered encoders, along with indexes which are rebuilt whenever an encoder is
+// registered so that lookups don't need to search every encoder.
+type encoderRegistry struct {
+	sync.RWMutex
+	encoders    map[string]Encoder
+	sorted      []Encoder
+	byExtension map[string]Encoder
+	byMIMEType  map[string]Encoder
+}
+
+// register adds an encoder to the registry and rebuilds the indexes. The registry must be locked for writing.
+func (r *encoderRegistry) register(e Encoder) {
+	r.encoders[strings.ToLower(e.Name)] = e
+	r.sorted = make([]Encoder, 0, len(r.encoders))
+
+	for _, re := range r.encoders {
+		r.sorted = append(r.sorted, re)
+	}
+
+	sort.Slice(r.sorted, func(i, j int) bool {
+		return strings.ToLower(r.sorted[i].Name) < strings.ToLower(r.sorted[j].Name)
+	})
+
+	r.byExtension = map[string]Encoder{}
+	r.byMIMEType = map[string]Encoder{}
+
+	// Encoders are indexed in reverse order so that the first encoder (alphabetically) wins any conflicts.
+	for i := len(r.sorted) - 1; i >= 0; i-- {
+		re := r.sorted[i]
+
+		for _, ext := range re.Extensions {
+			r.byExtension[strings.ToLower(ext)] = re
+		}
+
+		r.byMIMEType[strings.ToLower(re.MIMEType)] = re
+	}
+}
+
+// newEncoderRegistry returns a registry containing the specified encoders.
+func newEncoderRegistry(es ...Encoder) *encoderRegistry {
+	r := &encoderRegistry{encoders: map[string]Encoder{}}
+
+	for _, e := range es {
+		r.register(e)
+	}
+
+	return r
+}
+
+// encoders contains the registered encoders, starting with the built-in encoders.
+var encoders = newEncoderRegistry(
+	Encoder{
+		Name:       "gif",
+		MIMEType:   "image/gif",
+		Extensions: []string{".gif"},
+		Options:    []string{"colors"},
+		Encode:     encodeGIF,
+	},
+	Encoder{
+		Name:       "jpeg",
+		MIMEType:   "image/jpeg",
+		Extensions: []string{".jpg", ".jpeg"},
+		Options:    []string{"quality", "grayscale"},
+		Encode:     encodeJPEG,
+	},
+	Encoder{
+		Name:       "png",
+		MIMEType:   "image/png",
+		Extensions: []string{".png"},
+		Options:    []string{"compression"},
+		Encode:     encodePNG,
+	},
+)
+
+// RegisterEncoder registers an encoder, replacing any existing encoder with the same name.
+//
+// If more than one encoder uses the same extension or MIME type then the one with the first name (alphabetically) is
+// used.
+func RegisterEncoder(e Encoder) {
+	encoders.Lock()
+	defer encoders.Unlock()
+
+	encoders.register(e)
+}
+
+// Encoders returns the registered encoders, in alphabetical order by name.
+func Encoders() []Encoder {
+	encoders.RLock()
+	defer encoders.RUnlock()
+
+	es := make([]Encoder, len(encoders.sorted))
+	copy(es, encoders.sorted)
+
+	return es
+}
+
+// LookupEncoder returns the encoder for a file extension (with or without the leading dot).
+func LookupEncoder(ext string) (Encoder, bool) {
+	ext = strings.ToLower(ext)
+
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	encoders.RLock()
+	defer encoders.RUnlock()
+
+	e, ok := encoders.byExtension[ext]
+
+	return e, ok
+}
+
+// LookupEncoderByMIMEType returns the encoder for a MIME type, ignoring any parameters.
+func LookupEncoderByMIMEType(mimeType string) (Encoder, bool) {
+	t, _, err := mime.ParseMediaType(mimeType)
+
+	if err != nil {
+		return Encoder{}, false
+	}
+
+	encoders.RLock()
+	defer encoders.RUnlock()
+
+	e, ok := encoders.byMIMEType[t]
+
+	return e, ok
+}
+
+// intOption parses an integer option, returning def if it isn't set.
+func intOption(opts EncodeOptions, name string, def int) (int, error) {
+	v, ok := opts[name]
+
+	if !ok || len(v) == 0 {
+		return def, nil
+	}
+
+	i, err := strconv.Atoi(v)
+
+	if err != nil {
+		return 0, &EncodeOptionError{Option: name, Value: v}
+	}
+
+	return i, nil
+}
+
+// boolOption parses a boolean option (in any format supported by strconv.ParseBool), returning def if it isn't set.
+func boolOption(opts EncodeOptions, name string, def bool) (bool, error) {
+	v, ok := opts[name]
+
+	if !ok || len(v) == 0 {
+		return def, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+
+	if err != nil {
+		return false, &EncodeOptionError{Option: name, Value: v}
+	}
+
+	return b, nil
+}
+
+// pngCompressionLevels maps the values of the "compression" option to zlib compression levels.
+var pngCompressionLevels = map[string]int{
+	"none":    zlib.NoCompression,
+	"fast":    zlib.BestSpeed,
+	"default": zlib.DefaultCompression,
+	"best":    zlib.BestCompression,
+}
+
+// pngCompressionLevel returns the zlib compression level for the "compression" option (defaulting to "default").
+func pngCompressionLevel(opts EncodeOptions) (int, error) {
+	c, ok := opts["compression"]
+
+	if !ok || len(c) == 0 {
+		return zlib.DefaultCompression, nil
+	}
+
+	level, ok := pngCompressionLevels[strings.ToLower(c)]
+
+	if !ok {
+		return 0, &EncodeOptionError{Option: "compression", Value: c}
+	}
+
+	return level, nil
+}
+
+// encodeGIF encodes an image as a GIF, using the "colors" option as the maximum number of colors.
+//
+// Paletted images use all of the colors in their palette by default, and other images use up to 256 colors.
+func encodeGIF(w io.Writer, img image.Image, opts EncodeOptions) error {
+	def := 256
+	p, paletted := img.ColorModel().(color.Palette)
+
+	// Make sure we keep all of the colors in paletted images.
+	if paletted {
+		def = len(p)
+	}
+
+	n, err := intOption(opts, "colors", def)
+
+	if err != nil {
+		return err
+	}
+
+	if n < 1 || n > 256 {
+		return &EncodeOptionError{Option: "colors", Value: strconv.Itoa(n)}
+	}
+
+	// Paletted images other than *image.Paletted would otherwise be quantized to a different palette.
+	if paletted && len(p) <= 256 {
+		switch pi := img.(type) {
+		case *image.Paletted:
+		case *GridImage:
+			// Check the size before taking an image from the pool, as its pixels are sliced to fit the size.
+			if pi.size <= 0 || pi.size%8 != 0 {
+				return ErrInvalidSize
+			}
+
+			dst := getPalettedImage(pi.size)
+			defer palettedImages.Put(dst)
+
+			if err = RenderInto(dst, pi.grid, Palette{Background: pi.palette[0], Foreground: pi.palette[1]}); err != nil {
+				return err
+			}
+
+			img = dst
+		case image.PalettedImage:
+			img = ToPaletted(pi, p)
+		}
+	}
+
+	return gif.Encode(w, img, &gif.Options{NumColors: n})
+}
+
+// palettedImages contains a pool of images which grid images are drawn onto before being encoded.
+var palettedImages = sync.Pool{
+	New: func() interface{} {
+		return &image.Paletted{}
+	},
+}
+
+// getPalettedImage returns an image with the specified size from the pool, reusing its pixels if possible.
+func getPalettedImage(size int) *image.Paletted {
+	img, ok := palettedImages.Get().(*image.Paletted)
+
+	if !ok {
+		img = &image.Paletted{}
+	}
+
+	if cap(img.Pix) < size*size {
+		img.Pix = make([]uint8, size*size)
+	}
+
+	img.Pix = img.Pix[:size*size]
+	img.Stride = size
+	img.Rect = image.Rect(0, 0, size, size)
+
+	return img
+}
+
+// encodeJPEG encodes an image as a JPEG, using the "quality" option (1 to 100, defaulting to 100) and the "grayscale"
+// option (defaulting to false).
+func encodeJPEG(w io.Writer, img image.Image, opts EncodeOptions) error {
+	q, err := intOption(opts, "quality", 100)
+
+	if err != nil {
+		return err
+	}
+
+	if q < 1 || q > 100 {
+		return &EncodeOptionError{Option: "quality", Value: strconv.Itoa(q)}
+	}
+
+	gray, err := boolOption(opts, "grayscale", false)
+
+	if err != nil {
+		return err
+	}
+
+	// Grayscale images are written without any chroma, making them smaller.
+	if gray {
+		g := image.NewGray(img.Bounds())
+		draw.Draw(g, g.Bounds(), img, g.Bounds().Min, draw.Src)
+		img = g
+	}
+
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: q})
+}
+
+// encodePNG encodes an image as a PNG, using the "compression" option ("none", "fast", "default" or "best",
+// defaulting to "default").
+//
+// Paletted images are written using the smallest possible bit depth (one row at a time, so GridImage never needs to be
+// drawn in full), and everything else is written by image/png.
+func encodePNG(w io.Writer, img image.Image, opts EncodeOptions) error {
+	level, err := pngCompressionLevel(opts)
+
+	if err != nil {
+		return err
+	}
+
+	pal, _ := img.ColorModel().(color.Palette)
+
+	// Empty images and large palettes are left to image/png.
+	if !img.Bounds().Empty() && len(pal) > 0 && len(pal) <= 256 {
+		switch p := img.(type) {
+		case *GridImage:
+			return writeGridImagePNG(w, p, level)
+		case *image.Paletted:
+			return writePalettedImagePNG(w, p, level)
+		case image.PalettedImage:
+			return writePalettedImageRowsPNG(w, p, pal, level)
+		}
+	}
+
+	enc := png.Encoder{CompressionLevel: png.DefaultCompression}
+
+	switch level {
+	case zlib.NoCompression:
+		enc.CompressionLevel = png.NoCompression
+	case zlib.BestSpeed:
+		enc.CompressionLevel = png.BestSpeed
+	case zlib.BestCompression:
+		enc.CompressionLevel = png.BestCompression
+	}
+
+	return enc.Encode(w, img)
+}
@@ -0,0 +1,196 @@
+package ppic_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+func TestLookupEncoder(t *testing.T) {
+	cases := []struct {
+		ext  string
+		name string
+	}{
+		{ext: ".png", name: "png"},
+		{ext: "png", name: "png"},
+		{ext: ".PNG", name: "png"},
+		{ext: ".gif", name: "gif"},
+		{ext: ".jpg", name: "jpeg"},
+		{ext: "jpeg", name: "jpeg"},
+		{ext: ".bmp"},
+		{ext: ""},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.ext, func(t *testing.T) {
+			e, ok := ppic.LookupEncoder(c.ext)
+
+			if len(c.name) == 0 {
+				if ok {
+					t.Fatalf("expected no encoder but got %q", e.Name)
+				}
+
+				return
+			}
+
+			if !ok {
+				t.Fatal("expected an encoder to be found")
+			}
+
+			if e.Name != c.name {
+				t.Errorf("expected encoder %q but got %q", c.name, e.Name)
+			}
+		})
+	}
+}
+
+func TestLookupEncoderByMIMEType(t *testing.T) {
+	if e, ok := ppic.LookupEncoderByMIMEType("image/png; charset=binary"); !ok || e.Name != "png" {
+		t.Errorf("expected png encoder but got %q (found %t)", e.Name, ok)
+	}
+
+	if e, ok := ppic.LookupEncoderByMIMEType("IMAGE/JPEG"); !ok || e.Name != "jpeg" {
+		t.Errorf("expected jpeg encoder but got %q (found %t)", e.Name, ok)
+	}
+
+	if e, ok := ppic.LookupEncoderByMIMEType("image/bmp"); ok {
+		t.Errorf("expected no encoder but got %q", e.Name)
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	var names []string
+
+	for _, e := range ppic.Encoders() {
+		names = append(names, e.Name)
+	}
+
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("expected encoders to be sorted but got %v", names)
+	}
+}
+
+func TestEncoderOptions(t *testing.T) {
+	img := image.NewPaletted(image.Rect(0, 0, 8, 8), color.Palette{color.White, color.Black})
+
+	cases := []struct {
+		name   string
+		opts   ppic.EncodeOptions
+		decode func(io.Reader) (image.Image, error)
+		valid  bool
+	}{
+		{name: "png", decode: png.Decode, valid: true},
+		{name: "png", opts: ppic.EncodeOptions{"compression": "best"}, decode: png.Decode, valid: true},
+		{name: "png", opts: ppic.EncodeOptions{"compression": "foo"}},
+		{name: "jpeg", opts: ppic.EncodeOptions{"quality": "50"}, decode: jpeg.Decode, valid: true},
+		{name: "jpeg", opts: ppic.EncodeOptions{"quality": "0"}},
+		{name: "jpeg", opts: ppic.EncodeOptions{"quality": "foo"}},
+		{name: "gif", opts: ppic.EncodeOptions{"colors": "2"}, decode: gif.Decode, valid: true},
+		{name: "gif", opts: ppic.EncodeOptions{"colors": "257"}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			e, ok := ppic.LookupEncoder(c.name)
+
+			if !ok {
+				t.Fatalf("expected encoder %q to be registered", c.name)
+			}
+
+			buf := bytes.Buffer{}
+			err := e.Encode(&buf, img, c.opts)
+
+			if !c.valid {
+				if err == nil {
+					t.Fatalf("expected options %v to be invalid", c.opts)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to encode image: %s", err)
+			}
+
+			if _, err = c.decode(&buf); err != nil {
+				t.Errorf("failed to decode image: %s", err)
+			}
+		})
+	}
+}
+
+func TestEncodeGIFWithInvalidSize(t *testing.T) {
+	e, _ := ppic.LookupEncoder("gif")
+
+	// The zero value of a grid image has no size (or colors), and shouldn't be rendered into a pooled image.
+	if err := e.Encode(&bytes.Buffer{}, &ppic.GridImage{}, ppic.EncodeOptions{"colors": "2"}); err != ppic.ErrInvalidSize {
+		t.Errorf("expected error to be %q but got %v", ppic.ErrInvalidSize, err)
+	}
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	ppic.RegisterEncoder(ppic.Encoder{
+		Name:       "test",
+		MIMEType:   "image/x-test",
+		Extensions: []string{".test"},
+		Encode: func(w io.Writer, img image.Image, opts ppic.EncodeOptions) error {
+			_, err := io.WriteString(w, img.Bounds().String())
+
+			return err
+		},
+	})
+
+	if e, ok := ppic.LookupEncoderByMIMEType("image/x-test"); !ok || e.Name != "test" {
+		t.Fatalf("expected test encoder but got %q (found %t)", e.Name, ok)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/example.test?size=16", nil)
+	res := httptest.NewRecorder()
+
+	ppic.Handler(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status code %d but got %d", http.StatusOK, res.Code)
+	}
+
+	if ct := res.Header().Get("Content-Type"); ct != "image/x-test" {
+		t.Errorf("expected content type \"image/x-test\" but got %q", ct)
+	}
+
+	if body := res.Body.String(); body != "(0,0)-(16,16)" {
+		t.Errorf("expected body \"(0,0)-(16,16)\" but got %q", body)
+	}
+}
+
+func TestRegisterEncoderWithConflict(t *testing.T) {
+	// The encoder with the first name should be used regardless of the order they're registered in.
+	for _, name := range []string{"conflict-b", "conflict-a", "conflict-c"} {
+		ppic.RegisterEncoder(ppic.Encoder{
+			Name:       name,
+			MIMEType:   "image/x-conflict",
+			Extensions: []string{".conflict"},
+		})
+	}
+
+	if e, ok := ppic.LookupEncoder(".conflict"); !ok || e.Name != "conflict-a" {
+		t.Errorf("expected conflict-a encoder but got %q (found %t)", e.Name, ok)
+	}
+
+	if e, ok := ppic.LookupEncoderByMIMEType("image/x-conflict"); !ok || e.Name != "conflict-a" {
+		t.Errorf("expected conflict-a encoder but got %q (found %t)", e.Name, ok)
+	}
+}
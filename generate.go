@@ -7,11 +7,11 @@ import (
 	"math/rand"
 )
 
-// ErrInvalidSize is an error caused by specifying a size which is not a multiple of 8.
+// ErrInvalidSize is an error caused by specifying a size which is not a positive multiple of 8.
 var ErrInvalidSize = errors.New("size must be a multiple of 8")
 
 // Generate returns an 8x8 grid of values based on the provided source text, optionally mirrored along the X or Y axis.
-func Generate(k string, mX, mY bool) (img [8][8]bool) {
+func Generate(k string, mX, mY bool) (img Grid) {
 	// Hash the string and create a random number source from it.
 	hsh := hashString(k)
 	src := rand.NewSource(hsh)
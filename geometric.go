@@ -0,0 +1,202 @@
+package ppic
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// geometricPadding is the amount of space around the tiles, as a fraction of the image size.
+const geometricPadding = 0.08
+
+// shape is a set of polygons within a unit tile.
+type shape [][]point
+
+// circle returns a polygon approximating a circle within a unit tile.
+func circle(cx, cy, r float64) []point {
+	pts := make([]point, 32)
+
+	for i := range pts {
+		a := 2 * math.Pi * float64(i) / float64(len(pts))
+		pts[i] = point{cx + r*math.Cos(a), cy + r*math.Sin(a)}
+	}
+
+	return pts
+}
+
+// geometricOuterShapes are the shapes used for the corner and side tiles.
+var geometricOuterShapes = []shape{
+	{{{0, 0}, {1, 0}, {0, 1}}},
+	{{{0, 0}, {0.5, 0}, {0.5, 0.5}, {0, 0.5}}},
+	{{{0.5, 0}, {1, 0.5}, {0.5, 1}, {0, 0.5}}},
+	{{{0, 0}, {1, 0.5}, {0, 1}}},
+	{{{0, 0}, {1, 0}, {1, 0.5}, {0, 0.5}}},
+	{circle(0.5, 0.5, 0.35)},
+	{{{0, 0}, {1, 0.25}, {1, 1}, {0.25, 1}}},
+	{{{0.2, 0.2}, {0.8, 0.2}, {0.8, 0.8}, {0.2, 0.8}}},
+	{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}, circle(0.5, 0.5, 0.25)},
+}
+
+// geometricCenterShapes are the shapes used for the center tiles.
+var geometricCenterShapes = []shape{
+	{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+	{{{0.5, 0}, {1, 0.5}, {0.5, 1}, {0, 0.5}}},
+	{{{0, 0}, {1, 0}, {1, 1}}},
+	{{{0, 0}, {1, 0}, {1, 1}, {0.5, 0.5}}},
+	{circle(0.5, 0.5, 0.3)},
+	{{{0, 0}, {0.6, 0}, {1, 1}, {0.4, 1}}},
+	{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}, {{0.25, 0.25}, {0.75, 0.25}, {0.75, 0.75}, {0.25, 0.75}}},
+}
+
+// The positions of each tile in the 4x4 layout, ordered so that each tile is a quarter turn from the previous one.
+var (
+	geometricCorners = [][2]int{{0, 0}, {3, 0}, {3, 3}, {0, 3}}
+	geometricSides   = [][2]int{{1, 0}, {3, 1}, {2, 3}, {0, 2}, {2, 0}, {3, 2}, {1, 3}, {0, 1}}
+	geometricCenters = [][2]int{{1, 1}, {2, 1}, {2, 2}, {1, 2}}
+)
+
+// GeometricTile describes the tiles making up part of a geometric image.
+type GeometricTile struct {
+	// Shape is the index of the shape drawn in the tile.
+	Shape int
+
+	// Rotation is the number of quarter turns (clockwise) the shape is rotated by.
+	Rotation int
+
+	// Color is the index of the color used to draw the shape (see GeometricPalette).
+	Color int
+}
+
+// Geometric describes a geometric image, made up of corner, side and center tiles in a 4x4 layout.
+type Geometric struct {
+	Corner GeometricTile
+	Side   GeometricTile
+	Center GeometricTile
+}
+
+// GenerateGeometric returns a geometric image description based on the provided source text.
+func GenerateGeometric(k string) Geometric {
+	hsh := hashBytes(k)
+
+	// The first 2 bytes are used for the hue (see GenerateGeometricPalette).
+	tile := func(b []byte, shapes []shape) GeometricTile {
+		return GeometricTile{
+			Shape:    int(b[0]) % len(shapes),
+			Rotation: int(b[1]) % 4,
+			Color:    int(b[2]) % 3,
+		}
+	}
+
+	return Geometric{
+		Corner: tile(hsh[2:], geometricOuterShapes),
+		Side:   tile(hsh[5:], geometricOuterShapes),
+		Center: tile(hsh[8:], geometricCenterShapes),
+	}
+}
+
+// GenerateGeometricPalette generates a color palette from a string, using a hue derived from the string.
+func GenerateGeometricPalette(k string) Palette {
+	hsh := hashBytes(k)
+	hue := float64(uint16(hsh[0])<<8|uint16(hsh[1])) * 360 / 65536
+
+	return Palette{
+		Foreground: hslToRGB(hue, 0.5, 0.5),
+		Background: color.White,
+	}
+}
+
+// GeometricPalette returns the colors used to draw the tiles of a geometric image; the foreground color along with
+// lighter and darker shades of it.
+func GeometricPalette(p Palette) color.Palette {
+	return color.Palette{
+		p.Foreground,
+		mixColors(p.Foreground, color.White, 0.45),
+		mixColors(p.Foreground, color.Black, 0.35),
+	}
+}
+
+// shapes returns the shapes for each color of the image, in pixels.
+func (g Geometric) shapes(size int) [][][][]point {
+	shapes := make([][][][]point, 3)
+
+	pad := float64(size) * geometricPadding
+	tSize := (float64(size) - pad*2) / 4
+
+	add := func(t GeometricTile, set []shape, positions [][2]int) {
+		s := set[t.Shape%len(set)]
+		c := t.Color % len(shapes)
+
+		for i, pos := range positions {
+			// Each tile is rotated a further quarter turn so that the image is rotationally symmetric.
+			r := (t.Rotation + i) % 4
+
+			polys := make([][]point, len(s))
+
+			for j, pts := range s {
+				polys[j] = make([]point, len(pts))
+
+				for k, p := range pts {
+					// Rotate the point clockwise around the center of the tile.
+					for n := 0; n < r; n++ {
+						p = point{1 - p.Y, p.X}
+					}
+
+					polys[j][k] = point{
+						X: pad + (float64(pos[0])+p.X)*tSize,
+						Y: pad + (float64(pos[1])+p.Y)*tSize,
+					}
+				}
+			}
+
+			shapes[c] = append(shapes[c], polys)
+		}
+	}
+
+	add(g.Corner, geometricOuterShapes, geometricCorners)
+	add(g.Side, geometricOuterShapes, geometricSides)
+	add(g.Center, geometricCenterShapes, geometricCenters)
+
+	return shapes
+}
+
+// GenerateGeometricImage returns an anti-aliased image for the specified geometric image description.
+func GenerateGeometricImage(g Geometric, size int, p Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Rect, image.NewUniform(p.Background), image.Point{}, draw.Src)
+
+	pal := GeometricPalette(p)
+
+	for i, shapes := range g.shapes(size) {
+		if len(shapes) == 0 {
+			continue
+		}
+
+		mask := fillMask(img.Rect, shapes)
+		draw.DrawMask(img, img.Rect, image.NewUniform(pal[i]), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+
+	return img, nil
+}
+
+// GenerateGeometricSVG returns an SVG document for the specified geometric image description.
+func GenerateGeometricSVG(g Geometric, size int, p Palette) (string, error) {
+	if size <= 0 || size%8 != 0 {
+		return "", ErrInvalidSize
+	}
+
+	b := newSVGBuilder(size, p.Background, "")
+	pal := GeometricPalette(p)
+
+	for i, shapes := range g.shapes(size) {
+		for _, polys := range shapes {
+			b.path(pal[i], polys)
+		}
+	}
+
+	return b.String(), nil
+}
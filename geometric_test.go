@@ -0,0 +1,132 @@
+package ppic_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+func TestGenerateGeometric(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected ppic.Geometric
+	}{
+		{
+			text: "jackwilsdon",
+			expected: ppic.Geometric{
+				Corner: ppic.GeometricTile{Shape: 7, Rotation: 3, Color: 1},
+				Side:   ppic.GeometricTile{Shape: 0, Rotation: 3, Color: 2},
+				Center: ppic.GeometricTile{Shape: 4, Rotation: 0, Color: 1},
+			},
+		},
+		{
+			text: "",
+			expected: ppic.Geometric{
+				Corner: ppic.GeometricTile{Shape: 7, Rotation: 2, Color: 2},
+				Side:   ppic.GeometricTile{Shape: 0, Rotation: 0, Color: 2},
+				Center: ppic.GeometricTile{Shape: 0, Rotation: 3, Color: 1},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		name := c.text
+
+		if len(name) == 0 {
+			name = "[empty]"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			if act := ppic.GenerateGeometric(c.text); act != c.expected {
+				t.Errorf("expected %+v but got %+v", c.expected, act)
+			}
+		})
+	}
+}
+
+func TestGenerateGeometricPalette(t *testing.T) {
+	p := ppic.GenerateGeometricPalette("jackwilsdon")
+
+	if exp := (color.RGBA{R: 0x46, G: 0xBF, B: 0x40, A: 0xFF}); !colorsEqual(p.Foreground, exp) {
+		t.Errorf("expected foreground to be %v but got %v", exp, p.Foreground)
+	}
+
+	if !colorsEqual(p.Background, color.White) {
+		t.Errorf("expected background to be %v but got %v", color.White, p.Background)
+	}
+}
+
+func TestGenerateGeometricImage(t *testing.T) {
+	// Fill every tile with a square, using the foreground color for the outer tiles and the darker shade for the
+	// center tiles.
+	g := ppic.Geometric{
+		Corner: ppic.GeometricTile{Shape: 4, Color: 0},
+		Side:   ppic.GeometricTile{Shape: 4, Color: 0},
+		Center: ppic.GeometricTile{Shape: 0, Color: 2},
+	}
+
+	pal := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.White}
+	img, err := ppic.GenerateGeometricImage(g, 256, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gp := ppic.GeometricPalette(pal)
+
+	for _, c := range []struct {
+		x, y int
+		exp  color.Color
+	}{
+		{0, 0, pal.Background},
+		{255, 255, pal.Background},
+		{128, 128, gp[2]},
+		{30, 30, gp[0]},
+		{225, 225, gp[0]},
+	} {
+		if act := img.At(c.x, c.y); !colorsEqual(act, c.exp) {
+			t.Errorf("expected (%d, %d) to be %v but got %v", c.x, c.y, c.exp, act)
+		}
+	}
+}
+
+func TestGenerateGeometricImageWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateGeometricImage(ppic.GenerateGeometric("jackwilsdon"), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
+
+func TestGenerateGeometricSVG(t *testing.T) {
+	pal := ppic.GenerateGeometricPalette("jackwilsdon")
+	svg, err := ppic.GenerateGeometricSVG(ppic.GenerateGeometric("jackwilsdon"), 64, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>") {
+		t.Errorf("expected an SVG document but got %q", svg)
+	}
+
+	// Each of the 16 tiles is drawn as its own path.
+	if n := strings.Count(svg, "<path"); n != 16 {
+		t.Errorf("expected 16 paths but got %d", n)
+	}
+}
+
+func TestGenerateGeometricSVGWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateGeometricSVG(ppic.GenerateGeometric("jackwilsdon"), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
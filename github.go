@@ -0,0 +1,95 @@
+package ppic
+
+import (
+	"crypto/md5"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// gitHubBackground is the background color used by GitHub's identicons.
+var gitHubBackground = color.RGBA{R: 0xF0, G: 0xF0, B: 0xF0, A: 0xFF}
+
+// hashGitHub hashes the provided string into nibbles using MD5.
+func hashGitHub(k string) (n [32]uint8) {
+	m := md5.New()
+
+	// Write our string to the MD5 hash calculator.
+	fmt.Fprint(m, k)
+
+	for i, b := range m.Sum(nil) {
+		n[i*2] = b >> 4
+		n[i*2+1] = b & 0xF
+	}
+
+	return n
+}
+
+// GenerateGitHub returns a 5x5 grid of values based on the provided source text, mirrored along the Y axis in the
+// style of GitHub's identicons.
+func GenerateGitHub(k string) (grid [5][5]bool) {
+	n := hashGitHub(k)
+
+	// The first 15 nibbles fill the center column and the columns to the left of it (from the center out).
+	for i := 0; i < 15; i++ {
+		x := 2 - i/5
+		y := i % 5
+
+		grid[y][x] = n[i]%2 == 0
+		grid[y][4-x] = grid[y][x]
+	}
+
+	return grid
+}
+
+// GenerateGitHubPalette generates a color palette from a string in the style of GitHub's identicons.
+//
+// The foreground color is derived from the last 7 nibbles of the hash, which provide the hue, saturation and lightness.
+func GenerateGitHubPalette(k string) Palette {
+	n := hashGitHub(k)
+
+	h := int(n[25])<<8 | int(n[26])<<4 | int(n[27])
+	s := int(n[28])<<4 | int(n[29])
+	l := int(n[30])<<4 | int(n[31])
+
+	return Palette{
+		Foreground: hslToRGB(
+			float64(h)*360/4095,
+			(65-float64(s)*20/255)/100,
+			(75-float64(l)*20/255)/100,
+		),
+		Background: gitHubBackground,
+	}
+}
+
+// GenerateGitHubImage returns an image for the specified 5x5 grid, with a margin of half a cell around it.
+func GenerateGitHubImage(grid [5][5]bool, size int, p Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	// The grid and margins take up 6 cells, with any leftover space going into the margins.
+	cSize := size / 6
+	margin := (size - cSize*5) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), p.Palette())
+
+	for y, row := range grid {
+		for x, val := range row {
+			if !val {
+				continue
+			}
+
+			x0 := margin + x*cSize
+			y0 := margin + y*cSize
+
+			for cY := y0; cY < y0+cSize; cY++ {
+				for cX := x0; cX < x0+cSize; cX++ {
+					img.Pix[cY*img.Stride+cX] = 1
+				}
+			}
+		}
+	}
+
+	return img, nil
+}
@@ -0,0 +1,125 @@
+package ppic_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+func TestGenerateGitHub(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected [5]string
+	}{
+		{
+			text: "jackwilsdon",
+			expected: [5]string{
+				"#   #",
+				"  #  ",
+				"#####",
+				" ### ",
+				"     ",
+			},
+		},
+		{
+			text: "",
+			expected: [5]string{
+				"## ##",
+				"# # #",
+				"     ",
+				"## ##",
+				"# # #",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		name := c.text
+
+		if len(name) == 0 {
+			name = "[empty]"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			grid := ppic.GenerateGitHub(c.text)
+
+			for y, row := range grid {
+				for x, act := range row {
+					if exp := c.expected[y][x] == '#'; act != exp {
+						t.Errorf("expected grid[%d][%d] to be %t but got %t", y, x, exp, act)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateGitHubPalette(t *testing.T) {
+	p := ppic.GenerateGitHubPalette("jackwilsdon")
+
+	if exp := (color.RGBA{R: 0xD4, G: 0xC6, B: 0x6F, A: 0xFF}); !colorsEqual(p.Foreground, exp) {
+		t.Errorf("expected foreground to be %v but got %v", exp, p.Foreground)
+	}
+
+	if exp := (color.RGBA{R: 0xF0, G: 0xF0, B: 0xF0, A: 0xFF}); !colorsEqual(p.Background, exp) {
+		t.Errorf("expected background to be %v but got %v", exp, p.Background)
+	}
+}
+
+func TestGenerateGitHubImage(t *testing.T) {
+	grid := [5][5]bool{
+		{true, false, false, false, true},
+		{false, false, true, false, false},
+		{true, true, true, true, true},
+		{false, true, true, true, false},
+		{false, false, false, false, false},
+	}
+
+	pal := ppic.Palette{Foreground: color.Black, Background: color.White}
+	img, err := ppic.GenerateGitHubImage(grid, 240, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The image is 6 cells wide, with a margin of half a cell on each side.
+	cSize := 240 / 6
+
+	for y, row := range grid {
+		for x, val := range row {
+			exp := pal.Background
+
+			if val {
+				exp = pal.Foreground
+			}
+
+			cx := cSize/2 + x*cSize + cSize/2
+			cy := cSize/2 + y*cSize + cSize/2
+
+			if act := img.At(cx, cy); !colorsEqual(act, exp) {
+				t.Errorf("expected cell (%d, %d) to be %v but got %v", x, y, exp, act)
+			}
+		}
+	}
+
+	// The margin should always be the background color.
+	for i := 0; i < 240; i++ {
+		for _, pt := range [][2]int{{i, 0}, {0, i}, {i, 239}, {239, i}} {
+			if act := img.At(pt[0], pt[1]); !colorsEqual(act, pal.Background) {
+				t.Fatalf("expected margin at %v to be %v but got %v", pt, pal.Background, act)
+			}
+		}
+	}
+}
+
+func TestGenerateGitHubImageWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateGitHubImage(ppic.GenerateGitHub("jackwilsdon"), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
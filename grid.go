@@ -0,0 +1,227 @@
+package ppic
+
+import (
+	"encoding/json"
+	"errors"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidGrid is an error caused by parsing a grid which isn't in a supported format.
+var ErrInvalidGrid = errors.New("invalid grid")
+
+// Grid is an 8x8 grid of cells, indexed by row and then column, with set cells being drawn using the foreground color.
+type Grid [8][8]bool
+
+// GridFromUint64 returns the grid represented by a uint64, where the most significant bit is the top left cell and the
+// least significant bit is the bottom right cell.
+func GridFromUint64(v uint64) Grid {
+	var g Grid
+
+	for i := 0; i < 64; i++ {
+		g[i/8][i%8] = v&(1<<uint(63-i)) != 0
+	}
+
+	return g
+}
+
+// ParseGrid parses a grid from its text representation, which is either 16 hex digits (see MarshalText) or 8 lines of
+// 8 characters (see String).
+//
+// Set cells are '#' and unset cells are ' ' or '.', with missing characters at the end of a line being unset (so
+// trailing whitespace doesn't matter).
+func ParseGrid(s string) (Grid, error) {
+	var g Grid
+
+	if err := g.UnmarshalText([]byte(s)); err == nil {
+		return g, nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(strings.Replace(s, "\r\n", "\n", -1), "\n"), "\n")
+
+	if len(lines) != 8 {
+		return Grid{}, ErrInvalidGrid
+	}
+
+	for y, line := range lines {
+		if len(line) > 8 {
+			return Grid{}, ErrInvalidGrid
+		}
+
+		for x, c := range []byte(line) {
+			switch c {
+			case '#':
+				g[y][x] = true
+			case ' ', '.':
+			default:
+				return Grid{}, ErrInvalidGrid
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// String returns the grid as 8 lines of 8 characters, with '#' for set cells and ' ' for unset cells.
+func (g Grid) String() string {
+	b := make([]byte, 0, 8*9-1)
+
+	for y, row := range g {
+		if y > 0 {
+			b = append(b, '\n')
+		}
+
+		for _, val := range row {
+			if val {
+				b = append(b, '#')
+			} else {
+				b = append(b, ' ')
+			}
+		}
+	}
+
+	return string(b)
+}
+
+// Uint64 returns the grid as a uint64, where the most significant bit is the top left cell and the least significant
+// bit is the bottom right cell.
+func (g Grid) Uint64() uint64 {
+	var v uint64
+
+	for y, row := range g {
+		for x, val := range row {
+			if val {
+				v |= 1 << uint(63-(y*8+x))
+			}
+		}
+	}
+
+	return v
+}
+
+// MarshalText returns the grid as 16 hex digits (the result of Uint64).
+func (g Grid) MarshalText() ([]byte, error) {
+	s := strconv.FormatUint(g.Uint64(), 16)
+
+	return []byte(strings.Repeat("0", 16-len(s)) + s), nil
+}
+
+// UnmarshalText parses a grid from 16 hex digits.
+func (g *Grid) UnmarshalText(text []byte) error {
+	if len(text) != 16 {
+		return ErrInvalidGrid
+	}
+
+	v, err := strconv.ParseUint(string(text), 16, 64)
+
+	if err != nil {
+		return ErrInvalidGrid
+	}
+
+	*g = GridFromUint64(v)
+
+	return nil
+}
+
+// MarshalJSON returns the grid as a JSON array of rows, each containing 8 booleans.
+func (g Grid) MarshalJSON() ([]byte, error) {
+	return json.Marshal([8][8]bool(g))
+}
+
+// UnmarshalJSON parses a grid from either a JSON array of rows or a string containing 16 hex digits.
+func (g *Grid) UnmarshalJSON(data []byte) error {
+	// Like other types, null leaves the grid unchanged.
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		return g.UnmarshalText([]byte(s))
+	}
+
+	var rows [][]bool
+
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+
+	// Make sure the grid is the right size, as json ignores extra values (and leaves missing values unset) in arrays.
+	if len(rows) != 8 {
+		return ErrInvalidGrid
+	}
+
+	var r Grid
+
+	for y, row := range rows {
+		if len(row) != 8 {
+			return ErrInvalidGrid
+		}
+
+		copy(r[y][:], row)
+	}
+
+	*g = r
+
+	return nil
+}
+
+// Invert returns the grid with every cell flipped.
+func (g Grid) Invert() Grid {
+	return GridFromUint64(^g.Uint64())
+}
+
+// Rotate returns the grid rotated clockwise by the specified number of quarter turns (which can be negative).
+func (g Grid) Rotate(turns int) Grid {
+	turns = ((turns % 4) + 4) % 4
+
+	for ; turns > 0; turns-- {
+		var r Grid
+
+		for y, row := range g {
+			for x, val := range row {
+				r[x][7-y] = val
+			}
+		}
+
+		g = r
+	}
+
+	return g
+}
+
+// Flip returns the grid flipped horizontally (reversing each row) if x is set, and vertically (reversing the order of
+// the rows) if y is set.
+func (g Grid) Flip(x, y bool) Grid {
+	var f Grid
+
+	for cY, row := range g {
+		for cX, val := range row {
+			fX, fY := cX, cY
+
+			if x {
+				fX = 7 - cX
+			}
+
+			if y {
+				fY = 7 - cY
+			}
+
+			f[fY][fX] = val
+		}
+	}
+
+	return f
+}
+
+// Density returns the fraction of the cells in the grid which are set, from 0 to 1.
+func (g Grid) Density() float64 {
+	return float64(bits.OnesCount64(g.Uint64())) / 64
+}
+
+// Hamming returns the number of cells which differ between two grids.
+func (g Grid) Hamming(o Grid) int {
+	return bits.OnesCount64(g.Uint64() ^ o.Uint64())
+}
@@ -0,0 +1,262 @@
+package ppic_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+	"github.com/jackwilsdon/go-ppic/ppictest"
+)
+
+var testGrid = [8]string{
+	"# #  # #",
+	"# #### #",
+	"        ",
+	"# #  # #",
+	"  #  #  ",
+	"        ",
+	"##    ##",
+	"#      #",
+}
+
+func TestGridString(t *testing.T) {
+	grid := ppictest.Parse(testGrid)
+
+	if s := grid.String(); s != strings.Join(testGrid[:], "\n") {
+		t.Errorf("expected grid to be\n%s\nbut got\n%s", strings.Join(testGrid[:], "\n"), s)
+	}
+}
+
+func TestParseGrid(t *testing.T) {
+	cases := []struct {
+		name  string
+		text  string
+		valid bool
+	}{
+		{"String", strings.Join(testGrid[:], "\n"), true},
+		{"TrailingNewline", strings.Join(testGrid[:], "\n") + "\n", true},
+		{"CRLF", strings.Join(testGrid[:], "\r\n"), true},
+		{"Dots", strings.Replace(strings.Join(testGrid[:], "\n"), " ", ".", -1), true},
+		{"TrimmedLines", "# #  # #\n# #### #\n\n# #  # #\n  #  #\n\n##    ##\n#      #", true},
+		{"Hex", "a5bd00a52400c381", true},
+		{"UppercaseHex", "A5BD00A52400C381", true},
+		{"ShortHex", "a5bd00a52400c38", false},
+		{"InvalidHex", "a5bd00a52400c38g", false},
+		{"TooFewLines", strings.Join(testGrid[:7], "\n"), false},
+		{"TooManyLines", strings.Join(testGrid[:], "\n") + "\n        ", false},
+		{"LongLine", strings.Replace(strings.Join(testGrid[:], "\n"), "#      #", "#       #", 1), false},
+		{"InvalidCharacter", strings.Replace(strings.Join(testGrid[:], "\n"), "#      #", "#      x", 1), false},
+		{"Empty", "", false},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			grid, err := ppic.ParseGrid(c.text)
+
+			if !c.valid {
+				if err != ppic.ErrInvalidGrid {
+					t.Fatalf("expected error to be %q but got %v", ppic.ErrInvalidGrid, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = ppictest.Compare(grid, testGrid); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestGridUint64(t *testing.T) {
+	grid := ppictest.Parse(testGrid)
+
+	if v := grid.Uint64(); v != 0xA5BD00A52400C381 {
+		t.Errorf("expected 0xA5BD00A52400C381 but got %#x", v)
+	}
+
+	if g := ppic.GridFromUint64(0xA5BD00A52400C381); g != grid {
+		t.Errorf("expected grid to be\n%s\nbut got\n%s", grid, g)
+	}
+
+	text, err := ppic.GridFromUint64(1).MarshalText()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(text) != "0000000000000001" {
+		t.Errorf("expected %q but got %q", "0000000000000001", text)
+	}
+}
+
+func TestGridJSON(t *testing.T) {
+	grid := ppictest.Parse(testGrid)
+	data, err := json.Marshal(grid)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The grid should be written as rows of booleans.
+	rows, _ := json.Marshal([8][8]bool(grid))
+
+	if string(data) != string(rows) {
+		t.Errorf("expected %s but got %s", rows, data)
+	}
+
+	for _, data := range []string{string(rows), `"a5bd00a52400c381"`} {
+		var g ppic.Grid
+
+		if err = json.Unmarshal([]byte(data), &g); err != nil {
+			t.Fatalf("failed to unmarshal %s: %s", data, err)
+		}
+
+		if g != grid {
+			t.Errorf("expected grid to be\n%s\nbut got\n%s", grid, g)
+		}
+	}
+
+	for _, data := range []string{`"foo"`, `[[true]]`, `[]`, `{}`} {
+		var g ppic.Grid
+
+		if err = json.Unmarshal([]byte(data), &g); err == nil {
+			t.Errorf("expected %s to be invalid", data)
+		}
+	}
+}
+
+func TestGridTransforms(t *testing.T) {
+	grid := ppictest.Parse([8]string{
+		"##      ",
+		"#       ",
+		"        ",
+		"        ",
+		"        ",
+		"        ",
+		"        ",
+		"        ",
+	})
+
+	cases := []struct {
+		name     string
+		grid     ppic.Grid
+		expected [8]string
+	}{
+		{"Rotate", grid.Rotate(1), [8]string{
+			"      ##",
+			"       #",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+		}},
+		{"RotateBackwards", grid.Rotate(-1), [8]string{
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"#       ",
+			"##      ",
+		}},
+		{"RotateFull", grid.Rotate(4), [8]string{
+			"##      ",
+			"#       ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+		}},
+		{"FlipX", grid.Flip(true, false), [8]string{
+			"      ##",
+			"       #",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+		}},
+		{"FlipY", grid.Flip(false, true), [8]string{
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"#       ",
+			"##      ",
+		}},
+		{"FlipXY", grid.Flip(true, true), [8]string{
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"        ",
+			"       #",
+			"      ##",
+		}},
+		{"Invert", grid.Invert(), [8]string{
+			"  ######",
+			" #######",
+			"########",
+			"########",
+			"########",
+			"########",
+			"########",
+			"########",
+		}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			if err := ppictest.Compare(c.grid, c.expected); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestGridDensity(t *testing.T) {
+	grid := ppictest.Parse(testGrid)
+
+	if d := grid.Density(); d != 22.0/64 {
+		t.Errorf("expected density to be %v but got %v", 22.0/64, d)
+	}
+
+	if d := grid.Invert().Density(); d != 42.0/64 {
+		t.Errorf("expected density to be %v but got %v", 42.0/64, d)
+	}
+}
+
+func TestGridHamming(t *testing.T) {
+	grid := ppictest.Parse(testGrid)
+
+	if d := grid.Hamming(grid); d != 0 {
+		t.Errorf("expected distance to itself to be 0 but got %d", d)
+	}
+
+	if d := grid.Hamming(grid.Invert()); d != 64 {
+		t.Errorf("expected distance to inverted grid to be 64 but got %d", d)
+	}
+
+	if d := grid.Hamming(grid.Flip(true, false)); d != 0 {
+		t.Errorf("expected distance to mirrored grid to be 0 but got %d", d)
+	}
+}
@@ -1,11 +1,9 @@
 package ppic
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
-	"image"
-	"image/gif"
-	"image/jpeg"
-	"image/png"
 	"io"
 	"net/http"
 	"net/url"
@@ -14,8 +12,11 @@ import (
 	"strings"
 )
 
-// imageWriter represents a function which can write an image to a writer.
-type imageWriter func(io.Writer, image.Image) error
+// documentWriter represents a function which can generate a non-image representation of a key.
+type documentWriter struct {
+	contentType string
+	generate    func(k string, size int, p Palette) ([]byte, error)
+}
 
 // getImageSize extracts an image size from a set of URL values.
 func getImageSize(q url.Values) (int, error) {
@@ -31,35 +32,188 @@ func getImageSize(q url.Values) (int, error) {
 		return 0, err
 	}
 
+	// Empty (or negative) images can't be generated.
+	if s <= 0 {
+		return 0, ErrInvalidSize
+	}
+
 	return s, nil
 }
 
-// getImageWriter returns an imageWriter for the specified path.
-func getImageWriter(p string) imageWriter {
+// writeGenerateError writes an error which occurred during generation to the response.
+func writeGenerateError(res http.ResponseWriter, err error) {
+	// Check if an invalid size was specified.
+	if err == ErrInvalidSize {
+		res.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(res, "error: %s", err)
+
+		return
+	}
+
+	// Something else bad happened during generation.
+	res.WriteHeader(http.StatusInternalServerError)
+	fmt.Fprintf(res, "error: %s", err)
+}
+
+// writeEncodeError writes an error which occurred while encoding an image to the response.
+//
+// Encoders check their options before writing anything, so invalid options can still be reported as a bad request.
+func writeEncodeError(res http.ResponseWriter, err error) {
+	if _, ok := err.(*EncodeOptionError); ok {
+		res.Header().Del("Content-Type")
+		res.WriteHeader(http.StatusBadRequest)
+	}
+
+	fmt.Fprintf(res, "error: %s", err)
+}
+
+// getDocumentWriter returns a documentWriter for the specified path.
+func getDocumentWriter(p string) *documentWriter {
 	ext := path.Ext(p)
 
 	switch strings.ToLower(ext) {
-	case ".gif":
-		return func(w io.Writer, i image.Image) error {
-			return gif.Encode(w, i, &gif.Options{NumColors: 2})
+	case ".css":
+		return &documentWriter{
+			contentType: "text/css; charset=utf-8",
+			generate: func(k string, size int, p Palette) ([]byte, error) {
+				return []byte(GenerateTheme(p).CSS(":root")), nil
+			},
+		}
+	case ".glb":
+		return &documentWriter{
+			contentType: "model/gltf-binary",
+			generate: func(k string, size int, p Palette) ([]byte, error) {
+				buf := bytes.Buffer{}
+				err := GenerateMesh(Generate(k, true, false), DefaultMeshOptions).WriteGLB(&buf, p)
+
+				return buf.Bytes(), err
+			},
 		}
-	case ".jpg", ".jpeg":
-		return func(w io.Writer, i image.Image) error {
-			return jpeg.Encode(w, i, &jpeg.Options{Quality: 100})
+	case ".html":
+		return &documentWriter{
+			contentType: "text/html; charset=utf-8",
+			generate: func(k string, size int, p Palette) ([]byte, error) {
+				html, err := GenerateHTML(Generate(k, true, false), size, p)
+
+				return []byte(html), err
+			},
+		}
+	case ".json":
+		return &documentWriter{
+			contentType: "application/json",
+			generate: func(k string, size int, p Palette) ([]byte, error) {
+				m, err := GenerateMetadata(k, true, false, size, p)
+
+				if err != nil {
+					return nil, err
+				}
+
+				return json.Marshal(m)
+			},
 		}
-	case "", ".png":
-		return func(w io.Writer, i image.Image) error {
-			enc := png.Encoder{CompressionLevel: png.NoCompression}
+	case ".stl":
+		return &documentWriter{
+			contentType: "model/stl",
+			generate: func(k string, size int, p Palette) ([]byte, error) {
+				buf := bytes.Buffer{}
+				err := GenerateMesh(Generate(k, true, false), DefaultMeshOptions).WriteSTL(&buf)
 
-			return enc.Encode(w, i)
+				return buf.Bytes(), err
+			},
 		}
 	default:
 		return nil
 	}
 }
 
-// Handler serves HTTP requests with generated images.
+// HandlerConfig configures a handler created using NewHandler.
+type HandlerConfig struct {
+	// Style is the name of the style used when a request doesn't specify one (DefaultStyle if empty).
+	Style string
+
+	// EncodeOptions are the default options for each encoder, indexed by encoder name. Requests can override them
+	// using query parameters.
+	EncodeOptions map[string]EncodeOptions
+
+	// EncodeLimits restrict the values of numeric encoder options, indexed by encoder name and then option name. Limited
+	// options which aren't set by a request or by EncodeOptions use the maximum value of their limit.
+	EncodeLimits map[string]map[string]EncodeLimit
+}
+
+// handler serves HTTP requests with generated images.
+type handler struct {
+	config HandlerConfig
+}
+
+// NewHandler returns a handler which serves HTTP requests with generated images.
+func NewHandler(config HandlerConfig) http.Handler {
+	return handler{config: config}
+}
+
+// Handler serves HTTP requests with generated images, using the default configuration.
 func Handler(res http.ResponseWriter, req *http.Request) {
+	handler{}.ServeHTTP(res, req)
+}
+
+// getStyleOptions returns the options passed to styles from a set of URL values.
+func getStyleOptions(q url.Values) map[string]string {
+	opts := make(map[string]string, len(q))
+
+	for k := range q {
+		opts[k] = q.Get(k)
+	}
+
+	return opts
+}
+
+// getColors returns the colors which override a palette from a set of URL values ("fg" and "bg").
+func getColors(q url.Values) map[string]string {
+	colors := map[string]string{}
+
+	for _, k := range []string{"fg", "bg"} {
+		if v, ok := q[k]; ok {
+			colors[k] = v[0]
+		}
+	}
+
+	return colors
+}
+
+// getEncodeOptions returns the options passed to an encoder, combining the configured defaults with the options from a
+// set of URL values.
+func (h handler) getEncodeOptions(e Encoder, q url.Values) (EncodeOptions, error) {
+	opts := EncodeOptions{}
+
+	for k, v := range h.config.EncodeOptions[e.Name] {
+		opts[k] = v
+	}
+
+	for _, k := range e.Options {
+		if v := q.Get(k); len(v) > 0 {
+			opts[k] = v
+		}
+	}
+
+	for k, limit := range h.config.EncodeLimits[e.Name] {
+		v, ok := opts[k]
+
+		// The encoder's own default isn't known here, so limited options which haven't been set use the maximum.
+		if !ok {
+			opts[k] = strconv.Itoa(limit.Max)
+
+			continue
+		}
+
+		if err := limit.Check(k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return opts, nil
+}
+
+// ServeHTTP serves an HTTP request with a generated image.
+func (h handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	// We only support GETing images.
 	if req.Method != http.MethodGet {
 		res.Header().Set("Allow", http.MethodGet)
@@ -68,10 +222,20 @@ func Handler(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	writer := getImageWriter(req.URL.Path)
+	ext := strings.ToLower(path.Ext(req.URL.Path))
+
+	// Images are returned as PNGs if no extension is specified.
+	if len(ext) == 0 {
+		ext = ".png"
+	}
+
+	encoder, hasEncoder := LookupEncoder(ext)
+	document := getDocumentWriter(req.URL.Path)
+	svg := ext == ".svg"
+	text := ext == ".txt"
 
-	// If we couldn't find a writer then we couldn't understand the extension.
-	if writer == nil {
+	// If we couldn't find an encoder or writer then we couldn't understand the extension.
+	if !hasEncoder && document == nil && !svg && !text {
 		res.WriteHeader(http.StatusNotFound)
 		fmt.Fprintf(res, "error: unsupported file format")
 
@@ -79,6 +243,61 @@ func Handler(res http.ResponseWriter, req *http.Request) {
 	}
 
 	q := req.URL.Query()
+	name := q.Get("style")
+
+	if len(name) == 0 {
+		name = h.config.Style
+	}
+
+	style, ok := LookupStyle(name)
+
+	// Configure the style using the query (which may fail if the options aren't supported).
+	err := ErrUnsupportedStyle
+
+	if ok {
+		style, err = ConfigureStyle(style, getStyleOptions(q))
+	}
+
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(res, "error: %s", err)
+
+		return
+	}
+
+	_, pixel := style.(pixelStyle)
+	svgStyle, _ := style.(SVGStyle)
+	textStyle, _ := style.(TextStyle)
+
+	// Documents are generated from the pixel grid, so they can only be generated for the default style (and SVG
+	// documents and text can only be generated for styles which support them).
+	if (document != nil && !pixel) || (svg && svgStyle == nil) || (text && textStyle == nil) {
+		res.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(res, "error: %s", ErrUnsupportedStyle)
+
+		return
+	}
+
+	// SVG documents and text are generated by the style, but are otherwise treated like any other document.
+	if svg {
+		document = &documentWriter{
+			contentType: "image/svg+xml",
+			generate: func(k string, size int, p Palette) ([]byte, error) {
+				doc, err := svgStyle.SVG(k, size, p)
+
+				return []byte(doc), err
+			},
+		}
+	}
+
+	if text {
+		document = &documentWriter{
+			contentType: "text/plain; charset=utf-8",
+			generate: func(k string, size int, p Palette) ([]byte, error) {
+				return []byte(textStyle.Text(k) + "\n"), nil
+			},
+		}
+	}
 
 	// Get the image size from the request.
 	size, err := getImageSize(q)
@@ -93,37 +312,92 @@ func Handler(res http.ResponseWriter, req *http.Request) {
 	// Get the path without extension.
 	txt := strings.TrimSuffix(req.URL.Path[1:], path.Ext(req.URL.Path))
 
-	pal := DefaultPalette
+	_, mono := q["monochrome"]
+	pal, err := StylePalette(style, txt, mono, getColors(q))
 
-	// Generate a palette based on the source text if we're not in monochrome mode.
-	if _, mono := q["monochrome"]; !mono {
-		pal = GeneratePalette(txt)
-	}
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(res, "error: %s", err)
 
-	// Generate the grid.
-	grid := Generate(txt, true, false)
+		return
+	}
 
-	// Generate the image.
-	img, err := GenerateImage(grid, size, pal)
+	var out io.Writer = res
+	buf := bytes.Buffer{}
+	datauri := false
 
-	// Check if an invalid size was specified.
-	if err == ErrInvalidSize {
+	// If we're returning a data URI then we need to buffer the output so we can encode it.
+	switch q.Get("encoding") {
+	case "":
+	case "datauri":
+		out = &buf
+		datauri = true
+	default:
 		res.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(res, "error: %s", err)
+		fmt.Fprintf(res, "error: unsupported encoding")
 
 		return
 	}
 
-	// Check if something else bad happened during generation.
-	if err != nil {
-		res.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(res, "error: %s", err)
+	contentType := ""
 
-		return
+	// Generate a document instead of an image if one was requested.
+	if document != nil {
+		doc, err := document.generate(txt, size, pal)
+
+		if err != nil {
+			writeGenerateError(res, err)
+
+			return
+		}
+
+		contentType = document.contentType
+
+		if !datauri {
+			res.Header().Set("Content-Type", contentType)
+		}
+
+		if _, err = out.Write(doc); err != nil {
+			fmt.Fprintf(res, "error: %s", err)
+
+			return
+		}
+	} else {
+		opts, err := h.getEncodeOptions(encoder, q)
+
+		if err != nil {
+			res.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(res, "error: %s", err)
+
+			return
+		}
+
+		// Generate the image.
+		img, err := style.Image(txt, size, pal)
+
+		if err != nil {
+			writeGenerateError(res, err)
+
+			return
+		}
+
+		contentType = encoder.MIMEType
+
+		if !datauri {
+			res.Header().Set("Content-Type", contentType)
+		}
+
+		// Write the image to the response.
+		if err = encoder.Encode(out, img, opts); err != nil {
+			writeEncodeError(res, err)
+
+			return
+		}
 	}
 
-	// Write the image to the response.
-	if err = writer(res, img); err != nil {
-		fmt.Fprintf(res, "error: %s", err)
+	// Write the buffered output as a data URI if one was requested.
+	if datauri {
+		res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(res, DataURI(contentType, buf.Bytes()))
 	}
 }
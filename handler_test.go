@@ -2,6 +2,7 @@ package ppic_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"image"
 	"image/color"
 	_ "image/gif"
@@ -9,7 +10,6 @@ import (
 	_ "image/png"
 	"net/http"
 	"net/http/httptest"
-	"path"
 	"strings"
 	"testing"
 	"unicode"
@@ -29,21 +29,26 @@ func isPrintable(s string) bool {
 }
 
 func BenchmarkHandler(b *testing.B) {
-	paths := []string{
-		"/example.png",
-		"/example.gif",
-		"/example.jpg",
+	paths := []struct {
+		name string
+		path string
+	}{
+		{"PNG", "/example.png"},
+		{"GIF", "/example.gif"},
+		{"JPG", "/example.jpg"},
+		{"LargePNG", "/example.png?size=4096"},
+		{"LargeGIF", "/example.gif?size=4096"},
 	}
 
 	for _, p := range paths {
 		p := p
-		ext := path.Ext(p)
 
-		b.Run(strings.ToUpper(ext[1:]), func(b *testing.B) {
+		b.Run(p.name, func(b *testing.B) {
+			b.ReportAllocs()
 			b.StopTimer()
 
 			for n := 0; n < b.N; n++ {
-				req, err := http.NewRequest(http.MethodGet, p, nil)
+				req, err := http.NewRequest(http.MethodGet, p.path, nil)
 
 				if err != nil {
 					b.Fatalf("http.NewRequest: %s", err)
@@ -181,14 +186,20 @@ func TestHandlerSize(t *testing.T) {
 		{"/example?size=1024", 1024, http.StatusOK, ""},
 		{"/example?size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
 		{"/example?size=foo", 0, http.StatusBadRequest, "error: invalid size"},
+		{"/example?size=0", 0, http.StatusBadRequest, "error: invalid size"},
+		{"/example?size=-8", 0, http.StatusBadRequest, "error: invalid size"},
 		{"/example.png", 512, http.StatusOK, ""},
 		{"/example.png?size=1024", 1024, http.StatusOK, ""},
 		{"/example.png?size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
 		{"/example.png?size=foo", 0, http.StatusBadRequest, "error: invalid size"},
+		{"/example.png?size=0", 0, http.StatusBadRequest, "error: invalid size"},
+		{"/example.png?size=-8", 0, http.StatusBadRequest, "error: invalid size"},
 		{"/example.gif", 512, http.StatusOK, ""},
 		{"/example.gif?size=1024", 1024, http.StatusOK, ""},
 		{"/example.gif?size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
 		{"/example.gif?size=foo", 0, http.StatusBadRequest, "error: invalid size"},
+		{"/example.gif?size=0", 0, http.StatusBadRequest, "error: invalid size"},
+		{"/example.gif?size=-8", 0, http.StatusBadRequest, "error: invalid size"},
 		{"/example.jpg", 512, http.StatusOK, ""},
 		{"/example.jpg?size=1024", 1024, http.StatusOK, ""},
 		{"/example.jpg?size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
@@ -197,6 +208,37 @@ func TestHandlerSize(t *testing.T) {
 		{"/example.jpeg?size=1024", 1024, http.StatusOK, ""},
 		{"/example.jpeg?size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
 		{"/example.jpeg?size=foo", 0, http.StatusBadRequest, "error: invalid size"},
+		{"/example?style=isometric", 512, http.StatusOK, ""},
+		{"/example.gif?style=isometric", 512, http.StatusOK, ""},
+		{"/example?style=isometric&size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
+		{"/example?style=github", 512, http.StatusOK, ""},
+		{"/example.jpg?style=github&size=64", 64, http.StatusOK, ""},
+		{"/0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359?style=blockies", 512, http.StatusOK, ""},
+		{"/example?style=randomart", 512, http.StatusOK, ""},
+		{"/example.txt?style=randomart", 512, http.StatusOK, ""},
+		{"/example.txt?style=randomart&bits=foo", 0, http.StatusBadRequest, "error: invalid bits \"foo\""},
+		{"/example.txt?style=randomart&bits=-1", 0, http.StatusBadRequest, "error: invalid bits \"-1\""},
+		{"/example.txt", 0, http.StatusBadRequest, "error: unsupported style"},
+		{"/example?style=geometric", 512, http.StatusOK, ""},
+		{"/example.svg?style=geometric", 512, http.StatusOK, ""},
+		{"/example.svg?size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
+		{"/example.svg?style=isometric", 0, http.StatusBadRequest, "error: unsupported style"},
+		{"/example?style=initials", 512, http.StatusOK, ""},
+		{"/example.gif?style=initials&name=Jack+Wilsdon", 512, http.StatusOK, ""},
+		{"/example.svg?style=initials&size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
+		{"/example?style=rings", 512, http.StatusOK, ""},
+		{"/example?style=marble&size=64", 64, http.StatusOK, ""},
+		{"/example.svg?style=marble&size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
+		{"/example?style=parts", 512, http.StatusOK, ""},
+		{"/example.jpg?style=parts&set=robot", 512, http.StatusOK, ""},
+		{"/example?style=parts&set=foo", 0, http.StatusBadRequest, "error: unknown part set \"foo\""},
+		{"/example.svg?style=parts", 0, http.StatusBadRequest, "error: unsupported style"},
+		{"/example.svg?style=rings&size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
+		{"/example?style=foo", 0, http.StatusBadRequest, "error: unsupported style"},
+		{"/example.json?style=isometric", 0, http.StatusBadRequest, "error: unsupported style"},
+		{"/example.html", 512, http.StatusOK, ""},
+		{"/example.html?size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
+		{"/example.json?size=1023", 0, http.StatusBadRequest, "error: size must be a multiple of 8"},
 	}
 
 	for _, c := range cases {
@@ -348,3 +390,368 @@ func TestHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerMetadata(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/jackwilsdon.json?monochrome", nil)
+
+	if err != nil {
+		t.Fatalf("http.NewRequest: %s", err)
+	}
+
+	rec := httptest.NewRecorder()
+
+	ppic.Handler(rec, req)
+
+	res := rec.Result()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status to be %d but got %d", http.StatusOK, res.StatusCode)
+	}
+
+	if cType := res.Header.Get("Content-Type"); cType != "application/json" {
+		t.Errorf("expected content type to be %q but got %q", "application/json", cType)
+	}
+
+	var m struct {
+		Grid    ppic.Grid
+		Palette struct {
+			Foreground string
+			Background string
+		}
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		t.Fatalf("failed to parse metadata: %s", err)
+	}
+
+	err = ppictest.Compare(m.Grid, [8]string{
+		"# #  # #",
+		"# #### #",
+		"        ",
+		"# #  # #",
+		"  #  #  ",
+		"        ",
+		"##    ##",
+		"#      #",
+	})
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if m.Palette.Foreground != "#000000" || m.Palette.Background != "#ffffff" {
+		t.Errorf("expected palette to be #000000/#ffffff but got %s/%s", m.Palette.Foreground, m.Palette.Background)
+	}
+}
+
+func TestHandlerDocumentType(t *testing.T) {
+	cases := []struct {
+		path        string
+		contentType string
+		prefix      string
+	}{
+		{"/example.css", "text/css; charset=utf-8", ":root {"},
+		{"/example.html", "text/html; charset=utf-8", "<table"},
+		{"/example.json", "application/json", "{"},
+		{"/example.stl", "model/stl", "go-ppic"},
+		{"/example.svg", "image/svg+xml", "<svg"},
+		{"/example.svg?style=geometric", "image/svg+xml", "<svg"},
+		{"/example.svg?style=initials&name=Jack+Wilsdon", "image/svg+xml", "<svg"},
+		{"/example.svg?style=rings", "image/svg+xml", "<svg"},
+		{"/example.svg?style=marble", "image/svg+xml", "<svg"},
+		{"/example.glb", "model/gltf-binary", "glTF"},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.path[1:], func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, c.path, nil)
+
+			if err != nil {
+				t.Fatalf("http.NewRequest: %s", err)
+			}
+
+			rec := httptest.NewRecorder()
+
+			ppic.Handler(rec, req)
+
+			res := rec.Result()
+
+			if res.StatusCode != http.StatusOK {
+				t.Errorf("expected status to be %d but got %d", http.StatusOK, res.StatusCode)
+			}
+
+			if cType := res.Header.Get("Content-Type"); cType != c.contentType {
+				t.Errorf("expected content type to be %q but got %q", c.contentType, cType)
+			}
+
+			if body := rec.Body.String(); !strings.HasPrefix(body, c.prefix) {
+				t.Errorf("expected body to start with %q but got %q", c.prefix, body)
+			}
+		})
+	}
+}
+
+func TestHandlerDataURI(t *testing.T) {
+	cases := []struct {
+		path       string
+		statusCode int
+		prefix     string
+	}{
+		{"/example?encoding=datauri", http.StatusOK, "data:image/png;base64,"},
+		{"/example.gif?encoding=datauri", http.StatusOK, "data:image/gif;base64,"},
+		{"/example.css?encoding=datauri", http.StatusOK, "data:text/css;charset=utf-8;base64,"},
+		{"/example.html?encoding=datauri", http.StatusOK, "data:text/html;charset=utf-8;base64,"},
+		{"/example?encoding=datauri&size=1023", http.StatusBadRequest, "error: size must be a multiple of 8"},
+		{"/example?encoding=foo", http.StatusBadRequest, "error: unsupported encoding"},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.path[1:], func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, c.path, nil)
+
+			if err != nil {
+				t.Fatalf("http.NewRequest: %s", err)
+			}
+
+			rec := httptest.NewRecorder()
+
+			ppic.Handler(rec, req)
+
+			res := rec.Result()
+
+			if res.StatusCode != c.statusCode {
+				t.Errorf("expected status to be %d but got %d", c.statusCode, res.StatusCode)
+			}
+
+			if body := rec.Body.String(); !strings.HasPrefix(body, c.prefix) {
+				t.Errorf("expected body to start with %q but got %q", c.prefix, body)
+			}
+		})
+	}
+}
+
+func TestHandlerRandomartText(t *testing.T) {
+	path := "/SHA256:mSMKTEkh4f08bOVb2ePNoJeoGGRBfjHAiw0gWQfRxNE.txt?style=randomart&keytype=ED25519&bits=256"
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+
+	if err != nil {
+		t.Fatalf("http.NewRequest: %s", err)
+	}
+
+	rec := httptest.NewRecorder()
+
+	ppic.Handler(rec, req)
+
+	res := rec.Result()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status to be %d but got %d", http.StatusOK, res.StatusCode)
+	}
+
+	expected := "+--[ED25519 256]--+\n" +
+		"|+=BB==.o         |\n" +
+		"|+o.+=.E o        |\n" +
+		"| .o.+o.o         |\n" +
+		"| o .+o=  oo      |\n" +
+		"|  o  O..So +     |\n" +
+		"|   .+...o.+ *    |\n" +
+		"|    .. . o + o   |\n" +
+		"|      o . .      |\n" +
+		"|     . .         |\n" +
+		"+----[SHA256]-----+\n"
+
+	if body := rec.Body.String(); body != expected {
+		t.Errorf("expected body to be\n%s\nbut got\n%s", expected, body)
+	}
+
+	if cType := res.Header.Get("Content-Type"); cType != "text/plain; charset=utf-8" {
+		t.Errorf("expected content type to be %q but got %q", "text/plain; charset=utf-8", cType)
+	}
+}
+
+func TestHandlerEncodeOptions(t *testing.T) {
+	h := ppic.NewHandler(ppic.HandlerConfig{
+		EncodeOptions: map[string]ppic.EncodeOptions{
+			"png": {"compression": "best"},
+		},
+		EncodeLimits: map[string]map[string]ppic.EncodeLimit{
+			"jpeg": {"quality": {Min: 10, Max: 90}},
+		},
+	})
+
+	cases := []struct {
+		path       string
+		statusCode int
+		model      color.Model
+		body       string
+	}{
+		{"/example.jpg?quality=50", http.StatusOK, color.YCbCrModel, ""},
+		{"/example.jpg?quality=50&grayscale=true", http.StatusOK, color.GrayModel, ""},
+		{"/example.jpg?quality=95", http.StatusBadRequest, nil, "error: quality must be between 10 and 90"},
+		{"/example.jpg?quality=foo", http.StatusBadRequest, nil, "error: invalid quality \"foo\""},
+		{"/example.jpg?grayscale=foo", http.StatusBadRequest, nil, "error: invalid grayscale \"foo\""},
+		{"/example.gif?colors=2", http.StatusOK, nil, ""},
+		{"/example.gif?colors=0", http.StatusBadRequest, nil, "error: invalid colors \"0\""},
+		{"/example.png?compression=foo", http.StatusBadRequest, nil, "error: invalid compression \"foo\""},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.path[1:], func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, c.path, nil)
+
+			if err != nil {
+				t.Fatalf("http.NewRequest: %s", err)
+			}
+
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != c.statusCode {
+				t.Fatalf("expected status to be %d but got %d", c.statusCode, rec.Code)
+			}
+
+			if len(c.body) > 0 && rec.Body.String() != c.body {
+				t.Errorf("expected body to be %q but got %q", c.body, rec.Body.String())
+			}
+
+			if c.model != nil {
+				img, _, err := image.Decode(rec.Body)
+
+				if err != nil {
+					t.Fatalf("failed to decode image: %s", err)
+				}
+
+				if img.ColorModel() != c.model {
+					t.Errorf("expected color model %v but got %v", c.model, img.ColorModel())
+				}
+			}
+		})
+	}
+
+	// The configured PNG compression level should be used unless the request overrides it.
+	sizes := map[string]int{}
+
+	for _, path := range []string{"/example.png", "/example.png?compression=none"} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+
+		if err != nil {
+			t.Fatalf("http.NewRequest: %s", err)
+		}
+
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+		sizes[path] = rec.Body.Len()
+	}
+
+	if sizes["/example.png"] >= sizes["/example.png?compression=none"] {
+		t.Errorf("expected compressed image to be smaller but got %v", sizes)
+	}
+}
+
+// TestHandlerEncodeLimitsWithoutOption checks that limited options which aren't set use the maximum of their limit,
+// rather than the encoder's default (which may be outside of the limit).
+func TestHandlerEncodeLimitsWithoutOption(t *testing.T) {
+	h := ppic.NewHandler(ppic.HandlerConfig{
+		EncodeLimits: map[string]map[string]ppic.EncodeLimit{
+			"jpeg": {"quality": {Min: 10, Max: 50}},
+		},
+	})
+
+	bodies := map[string]string{}
+
+	for _, path := range []string{"/example.jpg", "/example.jpg?quality=50"} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+
+		if err != nil {
+			t.Fatalf("http.NewRequest: %s", err)
+		}
+
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status for %s to be %d but got %d", path, http.StatusOK, rec.Code)
+		}
+
+		bodies[path] = rec.Body.String()
+	}
+
+	if bodies["/example.jpg"] != bodies["/example.jpg?quality=50"] {
+		t.Errorf("expected image without a quality to use the maximum quality of the limit")
+	}
+}
+
+func TestHandlerColors(t *testing.T) {
+	generated := ppic.GeneratePalette("jackwilsdon")
+
+	cases := []struct {
+		path       string
+		statusCode int
+		palette    ppic.Palette
+	}{
+		{"/jackwilsdon.png?fg=red", http.StatusOK,
+			ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: generated.Background}},
+		{"/jackwilsdon.png?bg=%23000", http.StatusOK,
+			ppic.Palette{Foreground: generated.Foreground, Background: color.Black}},
+		{"/jackwilsdon.gif?fg=rgb(0,0,255)&bg=hsl(120,100%25,50%25)", http.StatusOK,
+			ppic.Palette{Foreground: color.RGBA{B: 0xFF, A: 0xFF}, Background: color.RGBA{G: 0xFF, A: 0xFF}}},
+		{"/jackwilsdon.png?monochrome&bg=yellow", http.StatusOK,
+			ppic.Palette{Foreground: color.Black, Background: color.RGBA{R: 0xFF, G: 0xFF, A: 0xFF}}},
+		{"/jackwilsdon.png?fg=foo", http.StatusBadRequest, ppic.Palette{}},
+		{"/jackwilsdon.css?bg=", http.StatusBadRequest, ppic.Palette{}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.path[1:], func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, c.path, nil)
+
+			if err != nil {
+				t.Fatalf("http.NewRequest: %s", err)
+			}
+
+			rec := httptest.NewRecorder()
+
+			ppic.Handler(rec, req)
+
+			if rec.Code != c.statusCode {
+				t.Fatalf("expected status to be %d but got %d (%s)", c.statusCode, rec.Code, rec.Body.String())
+			}
+
+			if c.palette.Foreground == nil {
+				return
+			}
+
+			img, _, err := image.Decode(rec.Body)
+
+			if err != nil {
+				t.Fatalf("failed to decode image: %s", err)
+			}
+
+			err = ppictest.CompareImage(img, c.palette, [8]string{
+				"# #  # #",
+				"# #### #",
+				"        ",
+				"# #  # #",
+				"  #  #  ",
+				"        ",
+				"##    ##",
+				"#      #",
+			})
+
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -6,13 +6,18 @@ import (
 	"fmt"
 )
 
-// hashString hashes the provided string into an integer.
-func hashString(s string) int64 {
+// hashBytes hashes the provided string using SHA256.
+func hashBytes(s string) []byte {
 	m := sha256.New()
 
 	// Write our string to the SHA256 hash calculator.
 	fmt.Fprint(m, s)
 
+	return m.Sum(nil)
+}
+
+// hashString hashes the provided string into an integer.
+func hashString(s string) int64 {
 	// Convert the first 8 bytes into a number.
-	return int64(binary.BigEndian.Uint64(m.Sum(nil)))
+	return int64(binary.BigEndian.Uint64(hashBytes(s)))
 }
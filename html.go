@@ -0,0 +1,73 @@
+package ppic
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+)
+
+// htmlCell writes a table cell spanning the specified number of columns.
+func htmlCell(buf *bytes.Buffer, span, pSize int, c color.Color) {
+	n := toNRGBA(c)
+	w := span * pSize
+
+	buf.WriteString("<td")
+
+	if span > 1 {
+		fmt.Fprintf(buf, ` colspan="%d"`, span)
+	}
+
+	// Older email clients only understand the attributes, newer ones prefer the styles.
+	fmt.Fprintf(buf, ` width="%d" height="%d" bgcolor="#%02x%02x%02x"`, w, pSize, n.R, n.G, n.B)
+	fmt.Fprintf(buf, ` style="width:%dpx;height:%dpx;padding:0;background-color:%s;`, w, pSize, hexColor(c))
+	buf.WriteString(`font-size:0;line-height:0;"></td>`)
+}
+
+// GenerateHTML returns an HTML table for the specified grid.
+//
+// The table uses inline styles and attributes only, so it displays correctly in places where images are blocked (such
+// as email clients).
+func GenerateHTML(grid Grid, size int, p Palette) (string, error) {
+	if size <= 0 || size%8 != 0 {
+		return "", ErrInvalidSize
+	}
+
+	// The size of each pixel in the table.
+	pSize := size / 8
+
+	buf := bytes.Buffer{}
+
+	buf.WriteString(`<table cellpadding="0" cellspacing="0" border="0"`)
+	fmt.Fprintf(&buf, ` width="%d" height="%d" role="presentation"`, size, size)
+	buf.WriteString(` style="border-collapse:collapse;border-spacing:0;table-layout:fixed;`)
+	fmt.Fprintf(&buf, `width:%dpx;height:%dpx;">`, size, size)
+
+	for _, row := range grid {
+		buf.WriteString("<tr>")
+
+		// Merge runs of the same value into a single cell to keep the markup small.
+		for x := 0; x < len(row); {
+			span := 1
+
+			for x+span < len(row) && row[x+span] == row[x] {
+				span++
+			}
+
+			c := p.Background
+
+			if row[x] {
+				c = p.Foreground
+			}
+
+			htmlCell(&buf, span, pSize, c)
+
+			x += span
+		}
+
+		buf.WriteString("</tr>")
+	}
+
+	buf.WriteString("</table>")
+
+	return buf.String(), nil
+}
@@ -0,0 +1,61 @@
+package ppic_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+	"github.com/jackwilsdon/go-ppic/ppictest"
+)
+
+func TestGenerateHTML(t *testing.T) {
+	grid := ppictest.Parse([8]string{
+		"########",
+		"        ",
+		"#      #",
+		"        ",
+		"        ",
+		"        ",
+		"        ",
+		"        ",
+	})
+
+	pal := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.White}
+	html, err := ppic.GenerateHTML(grid, 64, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(html, "<table") || !strings.HasSuffix(html, "</table>") {
+		t.Errorf("expected a table but got %q", html)
+	}
+
+	if n := strings.Count(html, "<tr>"); n != 8 {
+		t.Errorf("expected 8 rows but got %d", n)
+	}
+
+	// The first row is a single run and the third row is made up of three runs.
+	if n := strings.Count(html, "<td"); n != 1+1+3+5 {
+		t.Errorf("expected %d cells but got %d", 1+1+3+5, n)
+	}
+
+	if !strings.Contains(html, `colspan="8" width="64" height="8" bgcolor="#ff0000"`) {
+		t.Errorf("expected first row to be a single red cell but got %q", html)
+	}
+
+	if !strings.Contains(html, `colspan="6" width="48" height="8" bgcolor="#ffffff"`) {
+		t.Errorf("expected third row to contain a white run but got %q", html)
+	}
+}
+
+func TestGenerateHTMLWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateHTML(ppic.Generate("jackwilsdon", true, false), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
@@ -2,41 +2,29 @@ package ppic
 
 import (
 	"image"
-	"sync"
+	"image/color"
 )
 
-// rect draws a rectangle on the provided surface.
-func rect(img image.Paletted, x, y, size int, c uint8) {
-	x *= size
-	y *= size
+// RenderInto draws a grid onto an existing image, replacing its palette with the background and foreground colors.
+//
+// The image must be square, with a size which is a positive multiple of 8. Nothing is allocated if the image's palette
+// already has room for two colors, so images can be reused between calls (such as by keeping them in a sync.Pool).
+func RenderInto(dst *image.Paletted, grid Grid, p Palette) error {
+	size := dst.Rect.Dx()
 
-	for cY := y; cY < y+size; cY++ {
-		for cX := x; cX < x+size; cX++ {
-			img.Pix[(cY-img.Rect.Min.Y)*img.Stride+(cX-img.Rect.Min.X)] = c
-		}
+	if size <= 0 || size != dst.Rect.Dy() || size%8 != 0 {
+		return ErrInvalidSize
 	}
-}
 
-// GenerateImage returns an image for the specified grid.
-func GenerateImage(grid [8][8]bool, size int, p Palette) (image.Image, error) {
-	if size%8 != 0 {
-		return nil, ErrInvalidSize
-	}
+	dst.Palette = append(dst.Palette[:0], p.Background, p.Foreground)
 
 	// The size of each pixel in the image.
 	pSize := size / 8
 
-	// Create the image and image data.
-	img := image.NewPaletted(image.Rect(0, 0, size, size), p.Palette())
-
-	// Create a wait group so we can wait for all of our goroutines to finish.
-	wg := sync.WaitGroup{}
-
-	// There are going to be 64 goroutines (it's an 8x8 image).
-	wg.Add(64)
-
-	// Draw the image data onto the image.
 	for y, row := range grid {
+		// Draw the first line of the row.
+		first := dst.Pix[y*pSize*dst.Stride : y*pSize*dst.Stride+size]
+
 		for x, val := range row {
 			var c uint8
 
@@ -45,16 +33,96 @@ func GenerateImage(grid [8][8]bool, size int, p Palette) (image.Image, error) {
 				c = 1
 			}
 
-			// Draw the pixel.
-			go func(x, y int, c uint8) {
-				rect(*img, x, y, pSize, c)
-				wg.Done()
-			}(x, y, c)
+			for i := x * pSize; i < (x+1)*pSize; i++ {
+				first[i] = c
+			}
+		}
+
+		// Every other line of the row is the same as the first.
+		for i := 1; i < pSize; i++ {
+			copy(dst.Pix[(y*pSize+i)*dst.Stride:], first)
 		}
 	}
 
-	// Wait for all of the goroutines to finish.
-	wg.Wait()
+	return nil
+}
+
+// GenerateImage returns an image for the specified grid.
+func GenerateImage(grid Grid, size int, p Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), make(color.Palette, 0, 2))
+
+	if err := RenderInto(img, grid, p); err != nil {
+		return nil, err
+	}
 
 	return img, nil
 }
+
+// ToPaletted copies a paletted image (such as a GridImage) onto an *image.Paletted with the specified palette, keeping
+// the palette index of each pixel.
+func ToPaletted(img image.PalettedImage, p color.Palette) *image.Paletted {
+	b := img.Bounds()
+	dst := image.NewPaletted(b, p)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dst.SetColorIndex(x, y, img.ColorIndexAt(x, y))
+		}
+	}
+
+	return dst
+}
+
+// GridImage is an image which is drawn from a grid as it's read, rather than being stored in memory.
+//
+// This means that very large images take up almost no memory, although reading every pixel is slower than reading them
+// from the result of GenerateImage. PNG images are encoded from the grid one row at a time.
+type GridImage struct {
+	grid    Grid
+	size    int
+	palette color.Palette
+}
+
+// NewGridImage returns a GridImage for the specified grid, which must have a size which is a positive multiple of 8.
+func NewGridImage(grid Grid, size int, p Palette) (*GridImage, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	return &GridImage{grid: grid, size: size, palette: p.Palette()}, nil
+}
+
+// ColorModel returns the palette of the image.
+func (g *GridImage) ColorModel() color.Model {
+	return g.palette
+}
+
+// Bounds returns the bounds of the image.
+func (g *GridImage) Bounds() image.Rectangle {
+	return image.Rect(0, 0, g.size, g.size)
+}
+
+// At returns the color of the pixel at (x, y).
+func (g *GridImage) At(x, y int) color.Color {
+	return g.palette[g.ColorIndexAt(x, y)]
+}
+
+// ColorIndexAt returns the palette index of the pixel at (x, y), which is the background for pixels outside of the
+// image.
+func (g *GridImage) ColorIndexAt(x, y int) uint8 {
+	if x < 0 || y < 0 || x >= g.size || y >= g.size {
+		return 0
+	}
+
+	pSize := g.size / 8
+
+	if g.grid[y/pSize][x/pSize] {
+		return 1
+	}
+
+	return 0
+}
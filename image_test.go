@@ -2,6 +2,7 @@ package ppic_test
 
 import (
 	"fmt"
+	"image"
 	"image/color"
 	"testing"
 
@@ -21,11 +22,27 @@ func BenchmarkGenerateImage(b *testing.B) {
 		"#      #",
 	})
 
-	for n := 0; n < b.N; n++ {
-		if _, err := ppic.GenerateImage(grid, 512, ppic.DefaultPalette); err != nil {
-			b.Errorf("error: %s", err)
+	b.Run("GenerateImage", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for n := 0; n < b.N; n++ {
+			if _, err := ppic.GenerateImage(grid, 512, ppic.DefaultPalette); err != nil {
+				b.Errorf("error: %s", err)
+			}
 		}
-	}
+	})
+
+	b.Run("RenderInto", func(b *testing.B) {
+		img := image.NewPaletted(image.Rect(0, 0, 512, 512), make(color.Palette, 0, 2))
+
+		b.ReportAllocs()
+
+		for n := 0; n < b.N; n++ {
+			if err := ppic.RenderInto(img, grid, ppic.DefaultPalette); err != nil {
+				b.Errorf("error: %s", err)
+			}
+		}
+	})
 }
 
 func TestGenerateImage(t *testing.T) {
@@ -96,15 +113,123 @@ func TestGenerateImageWithInvalidSize(t *testing.T) {
 		"#      #",
 	})
 
-	_, err := ppic.GenerateImage(grid, 31, ppic.DefaultPalette)
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateImage(grid, size, ppic.DefaultPalette)
+
+		if err == nil || err != ppic.ErrInvalidSize {
+			msg := "nil"
+
+			if err != nil {
+				msg = fmt.Sprintf("%q", err)
+			}
+
+			t.Errorf("expected error for size %d to be %q but got %s", size, ppic.ErrInvalidSize, msg)
+		}
+	}
+}
+
+func TestGridImage(t *testing.T) {
+	expected := [8]string{
+		"# #  # #",
+		"# #### #",
+		"        ",
+		"# #  # #",
+		"  #  #  ",
+		"        ",
+		"##    ##",
+		"#      #",
+	}
 
-	if err == nil || err != ppic.ErrInvalidSize {
-		msg := "nil"
+	p := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.Black}
+	img, err := ppic.NewGridImage(ppictest.Parse(expected), 64, p)
 
-		if err != nil {
-			msg = fmt.Sprintf("%q", msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ppictest.CompareImage(img, p, expected); err != nil {
+		t.Error(err)
+	}
+
+	// Pixels outside of the image are the background.
+	for _, pt := range []image.Point{{-1, 0}, {0, -1}, {64, 0}, {0, 64}} {
+		if i := img.ColorIndexAt(pt.X, pt.Y); i != 0 {
+			t.Errorf("expected index at %v to be 0 but got %d", pt, i)
 		}
+	}
 
-		t.Errorf("expected error to be %q but got %s", ppic.ErrInvalidSize, msg)
+	for _, size := range []int{31, 0, -8} {
+		if _, err = ppic.NewGridImage(ppictest.Parse(expected), size, p); err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
+
+func TestToPaletted(t *testing.T) {
+	expected := [8]string{
+		"# #  # #",
+		"# #### #",
+		"        ",
+		"# #  # #",
+		"  #  #  ",
+		"        ",
+		"##    ##",
+		"#      #",
+	}
+
+	p := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.Black}
+	img, err := ppic.NewGridImage(ppictest.Parse(expected), 64, p)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := ppic.ToPaletted(img, p.Palette())
+
+	if err = ppictest.CompareImage(dst, p, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRenderInto(t *testing.T) {
+	expected := [8]string{
+		"# #  # #",
+		"# #### #",
+		"        ",
+		"# #  # #",
+		"  #  #  ",
+		"        ",
+		"##    ##",
+		"#      #",
+	}
+
+	grid := ppictest.Parse(expected)
+	p := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.Black}
+
+	// Use an image with a larger stride than it needs.
+	parent := image.NewPaletted(image.Rect(0, 0, 80, 80), nil)
+	img := parent.SubImage(image.Rect(0, 0, 64, 64)).(*image.Paletted)
+
+	if err := ppic.RenderInto(img, grid, p); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ppictest.CompareImage(img, p, expected); err != nil {
+		t.Error(err)
+	}
+
+	// Pixels outside of the image shouldn't be touched.
+	if i := parent.ColorIndexAt(64, 0); i != 0 {
+		t.Errorf("expected pixel outside of the image to be 0 but got %d", i)
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() { ppic.RenderInto(img, grid, p) }); allocs != 0 {
+		t.Errorf("expected no allocations but got %v", allocs)
+	}
+
+	for _, r := range []image.Rectangle{image.Rect(0, 0, 12, 12), image.Rect(0, 0, 16, 8), image.Rectangle{}} {
+		if err := ppic.RenderInto(image.NewPaletted(r, nil), grid, p); err != ppic.ErrInvalidSize {
+			t.Errorf("expected error to be %q for %v but got %v", ppic.ErrInvalidSize, r, err)
+		}
 	}
 }
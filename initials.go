@@ -0,0 +1,311 @@
+package ppic
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"unicode"
+)
+
+// The dimensions of each glyph in the initials font, in font pixels.
+const (
+	initialsGlyphWidth  = 5
+	initialsGlyphHeight = 7
+)
+
+// initialsScale is the number of font pixels which fit across the image.
+//
+// This leaves room for two glyphs (with a gap between them) to take up half of the width of the image.
+const initialsScale = (initialsGlyphWidth*2 + 1) * 2
+
+// initialsFallback is the glyph used for characters whose hash doesn't give a usable glyph. Text without any initials
+// isn't drawn with it, and only gets a plain background.
+const initialsFallback = '?'
+
+// initialsFont is the bitmap font used to draw initials, with '#' marking the pixels which are drawn.
+var initialsFont = map[rune][initialsGlyphHeight]string{
+	'A': {" ### ", "#   #", "#   #", "#####", "#   #", "#   #", "#   #"},
+	'B': {"#### ", "#   #", "#   #", "#### ", "#   #", "#   #", "#### "},
+	'C': {" ### ", "#   #", "#    ", "#    ", "#    ", "#   #", " ### "},
+	'D': {"#### ", "#   #", "#   #", "#   #", "#   #", "#   #", "#### "},
+	'E': {"#####", "#    ", "#    ", "#### ", "#    ", "#    ", "#####"},
+	'F': {"#####", "#    ", "#    ", "#### ", "#    ", "#    ", "#    "},
+	'G': {" ### ", "#   #", "#    ", "# ###", "#   #", "#   #", " ####"},
+	'H': {"#   #", "#   #", "#   #", "#####", "#   #", "#   #", "#   #"},
+	'I': {" ### ", "  #  ", "  #  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'J': {"  ###", "   # ", "   # ", "   # ", "   # ", "#  # ", " ##  "},
+	'K': {"#   #", "#  # ", "# #  ", "##   ", "# #  ", "#  # ", "#   #"},
+	'L': {"#    ", "#    ", "#    ", "#    ", "#    ", "#    ", "#####"},
+	'M': {"#   #", "## ##", "# # #", "# # #", "#   #", "#   #", "#   #"},
+	'N': {"#   #", "#   #", "##  #", "# # #", "#  ##", "#   #", "#   #"},
+	'O': {" ### ", "#   #", "#   #", "#   #", "#   #", "#   #", " ### "},
+	'P': {"#### ", "#   #", "#   #", "#### ", "#    ", "#    ", "#    "},
+	'Q': {" ### ", "#   #", "#   #", "#   #", "# # #", "#  # ", " ## #"},
+	'R': {"#### ", "#   #", "#   #", "#### ", "# #  ", "#  # ", "#   #"},
+	'S': {" ####", "#    ", "#    ", " ### ", "    #", "    #", "#### "},
+	'T': {"#####", "  #  ", "  #  ", "  #  ", "  #  ", "  #  ", "  #  "},
+	'U': {"#   #", "#   #", "#   #", "#   #", "#   #", "#   #", " ### "},
+	'V': {"#   #", "#   #", "#   #", "#   #", "#   #", " # # ", "  #  "},
+	'W': {"#   #", "#   #", "#   #", "# # #", "# # #", "# # #", " # # "},
+	'X': {"#   #", "#   #", " # # ", "  #  ", " # # ", "#   #", "#   #"},
+	'Y': {"#   #", "#   #", " # # ", "  #  ", "  #  ", "  #  ", "  #  "},
+	'Z': {"#####", "    #", "   # ", "  #  ", " #   ", "#    ", "#####"},
+	'0': {" ### ", "#   #", "#  ##", "# # #", "##  #", "#   #", " ### "},
+	'1': {"  #  ", " ##  ", "  #  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'2': {" ### ", "#   #", "    #", "   # ", "  #  ", " #   ", "#####"},
+	'3': {"#####", "   # ", "  #  ", "   # ", "    #", "#   #", " ### "},
+	'4': {"   # ", "  ## ", " # # ", "#  # ", "#####", "   # ", "   # "},
+	'5': {"#####", "#    ", "#### ", "    #", "    #", "#   #", " ### "},
+	'6': {"  ## ", " #   ", "#    ", "#### ", "#   #", "#   #", " ### "},
+	'7': {"#####", "    #", "   # ", "  #  ", " #   ", " #   ", " #   "},
+	'8': {" ### ", "#   #", "#   #", " ### ", "#   #", "#   #", " ### "},
+	'9': {" ### ", "#   #", "#   #", " ####", "    #", "   # ", " ##  "},
+	'?': {" ### ", "#   #", "    #", "   # ", "  #  ", "     ", "  #  "},
+}
+
+// initialsFolding maps accented Latin letters to the letters in the font used to draw them.
+var initialsFolding = foldInitials(map[rune]string{
+	'A': "ÀÁÂÃÄÅĀĂĄ",
+	'C': "ÇĆĈĊČ",
+	'D': "ĎĐ",
+	'E': "ÈÉÊËĒĔĖĘĚ",
+	'G': "ĜĞĠĢ",
+	'H': "ĤĦ",
+	'I': "ÌÍÎÏĨĪĬĮİ",
+	'J': "Ĵ",
+	'K': "Ķ",
+	'L': "ĹĻĽĿŁ",
+	'N': "ÑŃŅŇ",
+	'O': "ÒÓÔÕÖØŌŎŐ",
+	'R': "ŔŖŘ",
+	'S': "ŚŜŞŠ",
+	'T': "ŢŤŦ",
+	'U': "ÙÚÛÜŨŪŬŮŰŲ",
+	'W': "Ŵ",
+	'Y': "ÝŶŸ",
+	'Z': "ŹŻŽ",
+})
+
+// foldInitials returns a map from each accented letter to its base letter, from a map of base letters to the letters
+// which are folded into them.
+func foldInitials(letters map[rune]string) map[rune]rune {
+	folding := map[rune]rune{}
+
+	for base, l := range letters {
+		for _, r := range l {
+			folding[r] = base
+		}
+	}
+
+	return folding
+}
+
+// isInitialsSeparator returns whether a character separates the words of a name.
+func isInitialsSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(".-_+", r)
+}
+
+// isRegionalIndicator returns whether a character is a regional indicator symbol (used in pairs to make up flags).
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// firstGrapheme returns the first user-perceived character of a string, including any combining marks, emoji
+// modifiers and zero width joiner sequences which follow it.
+func firstGrapheme(s string) string {
+	rs := []rune(s)
+
+	if len(rs) == 0 {
+		return ""
+	}
+
+	i := 1
+
+	// Flags are made up of a pair of regional indicators.
+	if len(rs) > 1 && isRegionalIndicator(rs[0]) && isRegionalIndicator(rs[1]) {
+		i = 2
+	}
+
+	for i < len(rs) {
+		switch r := rs[i]; {
+		case unicode.Is(unicode.M, r), r >= 0x1F3FB && r <= 0x1F3FF:
+			i++
+		case r == 0x200D && i+1 < len(rs):
+			i += 2
+		default:
+			return string(rs[:i])
+		}
+	}
+
+	return string(rs)
+}
+
+// Initials returns up to two initials for a name, taken from the first and last words of the name.
+//
+// Each initial is a single user-perceived character, converted to upper case. Words are separated by whitespace,
+// periods, dashes, underscores and plus signs, and only the part of an email address before the "@" is used.
+func Initials(name string) []string {
+	if i := strings.IndexRune(name, '@'); i >= 0 {
+		name = name[:i]
+	}
+
+	words := strings.FieldsFunc(name, isInitialsSeparator)
+
+	if len(words) == 0 {
+		return nil
+	}
+
+	initials := []string{strings.ToUpper(firstGrapheme(words[0]))}
+
+	if len(words) > 1 {
+		initials = append(initials, strings.ToUpper(firstGrapheme(words[len(words)-1])))
+	}
+
+	return initials
+}
+
+// initialsGlyph returns the glyph used to draw an initial.
+func initialsGlyph(initial string) [initialsGlyphHeight]string {
+	r := initialsFallback
+
+	if len(initial) > 0 {
+		r = unicode.ToUpper([]rune(initial)[0])
+	}
+
+	if f, ok := initialsFolding[r]; ok {
+		r = f
+	}
+
+	if g, ok := initialsFont[r]; ok {
+		return g
+	}
+
+	return initialsHashGlyph(initial)
+}
+
+// initialsHashGlyph returns a glyph for a character which isn't in the font (such as CJK, Cyrillic or emoji), made up
+// of a horizontally mirrored pattern derived from the hash of the character.
+//
+// This means each character is always drawn the same way, and different characters are usually drawn differently.
+func initialsHashGlyph(initial string) [initialsGlyphHeight]string {
+	half := (initialsGlyphWidth + 1) / 2
+	hsh := uint64(hashString(initial))
+
+	// A pattern with no pixels set wouldn't draw anything.
+	if hsh&(1<<uint(half*initialsGlyphHeight)-1) == 0 {
+		return initialsFont[initialsFallback]
+	}
+
+	var g [initialsGlyphHeight]string
+
+	for y := range g {
+		row := []byte(strings.Repeat(" ", initialsGlyphWidth))
+
+		for x := 0; x < half; x++ {
+			if hsh&(1<<uint(y*half+x)) != 0 {
+				row[x] = '#'
+				row[initialsGlyphWidth-1-x] = '#'
+			}
+		}
+
+		g[y] = string(row)
+	}
+
+	return g
+}
+
+// GenerateInitialsPalette generates a color palette from a string, using the foreground color from GeneratePalette
+// as the background and either black or white (whichever has the most contrast) as the foreground.
+func GenerateInitialsPalette(k string) Palette {
+	bg := GeneratePalette(k).Foreground
+
+	// Black text has more contrast than white text when the luminance is above ~0.18.
+	if l := relativeLuminance(bg); (l+0.05)/0.05 > 1.05/(l+0.05) {
+		return Palette{Foreground: color.Black, Background: bg}
+	}
+
+	return Palette{Foreground: color.White, Background: bg}
+}
+
+// initialsPolygons returns the polygons making up the initials, in pixels.
+//
+// Each run of pixels in a row of a glyph is a separate polygon.
+func initialsPolygons(initials []string, size int) [][]point {
+	var polys [][]point
+
+	if len(initials) == 0 {
+		return polys
+	}
+
+	// The size of each font pixel, and the offset needed to center the initials.
+	pSize := float64(size) / initialsScale
+	width := len(initials)*(initialsGlyphWidth+1) - 1
+	oX := (float64(size) - float64(width)*pSize) / 2
+	oY := (float64(size) - initialsGlyphHeight*pSize) / 2
+
+	for i, initial := range initials {
+		for y, row := range initialsGlyph(initial) {
+			for x := 0; x < len(row); x++ {
+				if row[x] != '#' {
+					continue
+				}
+
+				start := x
+
+				for x < len(row) && row[x] == '#' {
+					x++
+				}
+
+				x0 := oX + float64(i*(initialsGlyphWidth+1)+start)*pSize
+				x1 := oX + float64(i*(initialsGlyphWidth+1)+x)*pSize
+				y0 := oY + float64(y)*pSize
+				y1 := y0 + pSize
+
+				polys = append(polys, []point{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}})
+			}
+		}
+	}
+
+	return polys
+}
+
+// GenerateInitialsImage returns an image of the initials drawn centered on the background.
+func GenerateInitialsImage(initials []string, size int, p Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Rect, image.NewUniform(p.Background), image.Point{}, draw.Src)
+
+	polys := initialsPolygons(initials, size)
+
+	if len(polys) == 0 {
+		return img, nil
+	}
+
+	// Each run is filled separately so that neighbouring runs are merged together instead of leaving seams.
+	shapes := make([][][]point, len(polys))
+
+	for i, pts := range polys {
+		shapes[i] = [][]point{pts}
+	}
+
+	mask := fillMask(img.Rect, shapes)
+	draw.DrawMask(img, img.Rect, image.NewUniform(p.Foreground), image.Point{}, mask, image.Point{}, draw.Over)
+
+	return img, nil
+}
+
+// GenerateInitialsSVG returns an SVG document of the initials drawn centered on the background.
+func GenerateInitialsSVG(initials []string, size int, p Palette) (string, error) {
+	if size <= 0 || size%8 != 0 {
+		return "", ErrInvalidSize
+	}
+
+	b := newSVGBuilder(size, p.Background, "")
+	b.path(p.Foreground, initialsPolygons(initials, size))
+
+	return b.String(), nil
+}
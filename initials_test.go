@@ -0,0 +1,221 @@
+package ppic_test
+
+import (
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+func TestInitials(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected []string
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"jackwilsdon", []string{"J"}},
+		{"Jack Wilsdon", []string{"J", "W"}},
+		{"jack.wilsdon@example.com", []string{"J", "W"}},
+		{"Jean-Luc Picard", []string{"J", "P"}},
+		{"  ada   lovelace  ", []string{"A", "L"}},
+		{"émile zola", []string{"É", "Z"}},
+		{"émile", []string{"É"}},
+		{"\U0001F44D\U0001F3FD fan", []string{"\U0001F44D\U0001F3FD", "F"}},
+		{"\U0001F468‍\U0001F469‍\U0001F467 family", []string{"\U0001F468‍\U0001F469‍\U0001F467", "F"}},
+		{"\U0001F1EC\U0001F1E7", []string{"\U0001F1EC\U0001F1E7"}},
+		{"李小龙", []string{"李"}},
+	}
+
+	for _, c := range cases {
+		c := c
+		name := c.name
+
+		if len(strings.TrimSpace(name)) == 0 {
+			name = "[empty]"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			if act := ppic.Initials(c.name); !reflect.DeepEqual(act, c.expected) {
+				t.Errorf("expected %q but got %q", c.expected, act)
+			}
+		})
+	}
+}
+
+func TestGenerateInitialsPalette(t *testing.T) {
+	cases := []struct {
+		text       string
+		foreground color.Color
+	}{
+		{"jackwilsdon", color.Black},
+		{"test", color.White},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.text, func(t *testing.T) {
+			p := ppic.GenerateInitialsPalette(c.text)
+
+			if exp := ppic.GeneratePalette(c.text).Foreground; !colorsEqual(p.Background, exp) {
+				t.Errorf("expected background to be %v but got %v", exp, p.Background)
+			}
+
+			if !colorsEqual(p.Foreground, c.foreground) {
+				t.Errorf("expected foreground to be %v but got %v", c.foreground, p.Foreground)
+			}
+		})
+	}
+}
+
+func TestGenerateInitialsImage(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.Black, Background: color.White}
+
+	// With a size of 176 each font pixel is 8 pixels, and a single initial is 5 font pixels wide and 7 tall.
+	img, err := ppic.GenerateInitialsImage([]string{"L"}, 176, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oX, oY := (176-5*8)/2, (176-7*8)/2
+
+	for _, c := range []struct {
+		x, y int
+		exp  color.Color
+	}{
+		{0, 0, pal.Background},
+		{oX - 1, oY, pal.Background},
+		{oX, oY, pal.Foreground},
+		{oX + 8, oY, pal.Background},
+		{oX + 39, oY + 55, pal.Foreground},
+		{oX + 40, oY + 55, pal.Background},
+		{oX, oY + 56, pal.Background},
+	} {
+		if act := img.At(c.x, c.y); !colorsEqual(act, c.exp) {
+			t.Errorf("expected (%d, %d) to be %v but got %v", c.x, c.y, c.exp, act)
+		}
+	}
+}
+
+func TestGenerateInitialsImageWithoutInitials(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.Black, Background: color.White}
+	img, err := ppic.GenerateInitialsImage(nil, 64, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if act := img.At(x, y); !colorsEqual(act, pal.Background) {
+				t.Fatalf("expected (%d, %d) to be %v but got %v", x, y, pal.Background, act)
+			}
+		}
+	}
+}
+
+func TestGenerateInitialsImageWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateInitialsImage([]string{"J"}, size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
+
+func TestGenerateInitialsSVGWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateInitialsSVG([]string{"J"}, size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
+
+func TestGenerateInitialsSVG(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.Black, Background: color.White}
+	svg, err := ppic.GenerateInitialsSVG([]string{"I"}, 176, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "I" is drawn as 7 runs; the top and bottom bars and the 5 pixels of the stem.
+	exp := `<svg xmlns="http://www.w3.org/2000/svg" width="176" height="176" viewBox="0 0 176 176">` +
+		`<rect width="176" height="176" fill="#ffffff"/>` +
+		`<path fill="#000000" fill-rule="evenodd" d="` +
+		`M76 60L100 60L100 68L76 68Z ` +
+		`M84 68L92 68L92 76L84 76Z ` +
+		`M84 76L92 76L92 84L84 84Z ` +
+		`M84 84L92 84L92 92L84 92Z ` +
+		`M84 92L92 92L92 100L84 100Z ` +
+		`M84 100L92 100L92 108L84 108Z ` +
+		`M76 108L100 108L100 116L76 116Z"/>` +
+		`</svg>`
+
+	if svg != exp {
+		t.Errorf("expected SVG to be %q but got %q", exp, svg)
+	}
+}
+
+func TestGenerateInitialsSVGWithUnknownCharacter(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.Black, Background: color.White}
+	svgs := map[string]string{}
+
+	// Characters which aren't in the font are drawn using a glyph derived from their hash, rather than all being drawn
+	// the same way.
+	for _, initial := range []string{"?", "李", "王", "Ж", "😀"} {
+		svg, err := ppic.GenerateInitialsSVG([]string{initial}, 64, pal)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for other, otherSVG := range svgs {
+			if svg == otherSVG {
+				t.Errorf("expected %q to be drawn differently to %q", initial, other)
+			}
+		}
+
+		again, err := ppic.GenerateInitialsSVG([]string{initial}, 64, pal)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if svg != again {
+			t.Errorf("expected %q to be drawn the same way every time", initial)
+		}
+
+		svgs[initial] = svg
+	}
+}
+
+func TestGenerateInitialsSVGWithAccent(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.Black, Background: color.White}
+
+	exp, err := ppic.GenerateInitialsSVG([]string{"E"}, 64, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Accented letters are drawn without their accents.
+	for _, initial := range []string{"É", "É", "é"} {
+		act, err := ppic.GenerateInitialsSVG([]string{initial}, 64, pal)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if act != exp {
+			t.Errorf("expected SVG for %q to be %q but got %q", initial, exp, act)
+		}
+	}
+}
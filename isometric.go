@@ -0,0 +1,84 @@
+package ppic
+
+import (
+	"image"
+	"image/color"
+)
+
+// Indexes of the colors in the isometric image palette.
+const (
+	isoBackground = iota
+	isoTop
+	isoLeft
+	isoRight
+	isoFloorTop
+	isoFloorLeft
+	isoFloorRight
+)
+
+// isometricPalette returns the palette used for isometric images, with shades for each visible face of a block.
+func isometricPalette(p Palette) color.Palette {
+	floor := mixColors(p.Background, color.Black, 0.08)
+
+	return color.Palette{
+		isoBackground: p.Background,
+		isoTop:        p.Foreground,
+		isoLeft:       mixColors(p.Foreground, color.Black, 0.25),
+		isoRight:      mixColors(p.Foreground, color.Black, 0.45),
+		isoFloorTop:   floor,
+		isoFloorLeft:  mixColors(floor, color.Black, 0.2),
+		isoFloorRight: mixColors(floor, color.Black, 0.35),
+	}
+}
+
+// GenerateIsometricImage returns an image for the specified grid, drawn as isometric blocks on a floor.
+//
+// Each foreground cell is drawn as a cube with its top, left and right faces shaded using the foreground color.
+func GenerateIsometricImage(grid Grid, size int, p Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), isometricPalette(p))
+
+	// The width of a single tile, and the height of the blocks and floor.
+	tw := float64(size / 8)
+	bh := tw / 2
+	fh := tw / 4
+
+	// Center the drawing vertically (the floor is half as tall as it is wide).
+	oy := (float64(size) - (float64(size)/2 + bh + fh)) / 2
+
+	// project converts a point on the grid (with a height in pixels) into a point on the image.
+	project := func(x, y, z float64) point {
+		return point{
+			X: float64(size)/2 + (x-y)*tw/2,
+			Y: oy + bh + (x+y)*tw/4 - z,
+		}
+	}
+
+	// block draws a box covering the grid area from (x0, y0) to (x1, y1), from z0 to z1.
+	block := func(x0, y0, x1, y1, z0, z1 float64, top, left, right uint8) {
+		fillPolygon(img, []point{project(x0, y1, z0), project(x1, y1, z0), project(x1, y1, z1), project(x0, y1, z1)}, left)
+		fillPolygon(img, []point{project(x1, y0, z0), project(x1, y1, z0), project(x1, y1, z1), project(x1, y0, z1)}, right)
+		fillPolygon(img, []point{project(x0, y0, z1), project(x1, y0, z1), project(x1, y1, z1), project(x0, y1, z1)}, top)
+	}
+
+	// Draw the floor first, as everything else sits on top of it.
+	block(0, 0, 8, 8, -fh, 0, isoFloorTop, isoFloorLeft, isoFloorRight)
+
+	// Draw the blocks from back to front so that the closer ones cover the ones behind them.
+	for d := 0; d < 15; d++ {
+		for y := 0; y < 8; y++ {
+			x := d - y
+
+			if x < 0 || x >= 8 || !grid[y][x] {
+				continue
+			}
+
+			block(float64(x), float64(y), float64(x+1), float64(y+1), 0, bh, isoTop, isoLeft, isoRight)
+		}
+	}
+
+	return img, nil
+}
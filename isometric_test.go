@@ -0,0 +1,86 @@
+package ppic_test
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+	"github.com/jackwilsdon/go-ppic/ppictest"
+)
+
+func BenchmarkGenerateIsometricImage(b *testing.B) {
+	grid := ppic.Generate("jackwilsdon", true, false)
+
+	for n := 0; n < b.N; n++ {
+		if _, err := ppic.GenerateIsometricImage(grid, 512, ppic.DefaultPalette); err != nil {
+			b.Errorf("error: %s", err)
+		}
+	}
+}
+
+func TestGenerateIsometricImage(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.White}
+	size := 256
+
+	// A single block in the top corner of the grid.
+	grid := ppictest.Parse([8]string{
+		"#       ",
+		"        ",
+		"        ",
+		"        ",
+		"        ",
+		"        ",
+		"        ",
+		"        ",
+	})
+
+	img, err := ppic.GenerateIsometricImage(grid, size, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b := img.Bounds(); b.Dx() != size || b.Dy() != size {
+		t.Fatalf("expected image to be %dx%d but got %dx%d", size, size, b.Dx(), b.Dy())
+	}
+
+	tw := size / 8
+	oy := (size - (size/2 + tw/2 + tw/4)) / 2
+
+	cases := []struct {
+		x, y     int
+		expected color.Color
+	}{
+		// The corners of the image are outside of the floor.
+		{0, 0, pal.Background},
+		{size - 1, size - 1, pal.Background},
+		// The top of the block is the foreground color.
+		{size / 2, oy + tw/4, pal.Foreground},
+		// The front faces of the block are shaded.
+		{size/2 - tw/4, oy + tw/2 + tw/4, color.RGBA{R: 0xBF, A: 0xFF}},
+		{size/2 + tw/4, oy + tw/2 + tw/4, color.RGBA{R: 0x8C, A: 0xFF}},
+		// The middle of the floor is a shade of the background color.
+		{size / 2, oy + tw/2 + size/4, color.RGBA{R: 0xEB, G: 0xEB, B: 0xEB, A: 0xFF}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(fmt.Sprintf("%d,%d", c.x, c.y), func(t *testing.T) {
+			if act := img.At(c.x, c.y); !colorsEqual(act, c.expected) {
+				t.Errorf("expected color to be %v but got %v", c.expected, act)
+			}
+		})
+	}
+}
+
+func TestGenerateIsometricImageWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateIsometricImage(ppic.Generate("jackwilsdon", true, false), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package ppic
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// marbleBlur is the standard deviation of the blur applied to the blobs, as a fraction of the image size.
+const marbleBlur = 0.07
+
+// marbleSteps is the number of points used to approximate the outline of a blob.
+const marbleSteps = 64
+
+// marbleMaskSize is the largest size the blobs are blurred at, with larger images scaling up the blurred blobs (which
+// don't have enough detail to lose any when scaled up) rather than blurring them at full size.
+const marbleMaskSize = 256
+
+// MarbleBlob describes a blurred ellipse drawn on top of the gradient of a marble image.
+type MarbleBlob struct {
+	// X and Y are the position of the center of the blob, as a fraction of the image size.
+	X, Y float64
+
+	// Radius is the horizontal radius of the blob (before rotation), as a fraction of the image size.
+	Radius float64
+
+	// Stretch is the vertical radius of the blob as a fraction of its horizontal radius.
+	Stretch float64
+
+	// Rotation is the angle the blob is rotated by, in degrees clockwise.
+	Rotation float64
+
+	// Color is the index of the color used to draw the blob (see MarbleColors).
+	Color int
+}
+
+// Marble describes an abstract image made up of a linear gradient with blurred blobs drawn on top of it.
+type Marble struct {
+	// Angle is the direction of the gradient, in degrees clockwise from left-to-right.
+	Angle float64
+
+	// Blobs are the blobs drawn on top of the gradient, in the order they're drawn.
+	Blobs [3]MarbleBlob
+}
+
+// GenerateMarble returns a marble image description based on the provided source text.
+func GenerateMarble(k string) Marble {
+	hsh := hashBytes(k)
+
+	// frac returns a byte of the hash as a value between lo and hi.
+	frac := func(i int, lo, hi float64) float64 {
+		return lo + (hi-lo)*float64(hsh[i])/0xFF
+	}
+
+	m := Marble{Angle: float64(hsh[0]) * 360 / 256}
+
+	for i := range m.Blobs {
+		o := 1 + i*6
+
+		m.Blobs[i] = MarbleBlob{
+			X:        frac(o, 0.1, 0.9),
+			Y:        frac(o+1, 0.1, 0.9),
+			Radius:   frac(o+2, 0.2, 0.45),
+			Stretch:  frac(o+3, 0.4, 1),
+			Rotation: float64(hsh[o+4]) * 180 / 256,
+			Color:    3 + int(hsh[o+5])%2,
+		}
+	}
+
+	return m
+}
+
+// MarbleColors returns the colors used to draw a marble image; the first 3 are the stops of the gradient, and the
+// rest are used for the blobs.
+//
+// The colors are all derived from the foreground color, with the last one being a mix of the foreground and
+// background colors.
+func MarbleColors(p Palette) color.Palette {
+	h, s, l := rgbToHSL(p.Foreground)
+
+	return color.Palette{
+		p.Foreground,
+		hslToRGB(h+40, s, math.Min(1, l+0.15)),
+		hslToRGB(h-40, s, l),
+		hslToRGB(h+150, s, l),
+		mixColors(p.Foreground, p.Background, 0.7),
+	}
+}
+
+// gradientColor returns the color at t (between 0 and 1) along a gradient with evenly spaced stops.
+func gradientColor(stops []color.Color, t float64) color.NRGBA {
+	t = math.Max(0, math.Min(1, t)) * float64(len(stops)-1)
+	i := int(math.Min(math.Floor(t), float64(len(stops)-2)))
+
+	return mixColors(stops[i], stops[i+1], t-float64(i))
+}
+
+// outline returns the polygon approximating the outline of the blob, in pixels.
+func (b MarbleBlob) outline(size int) []point {
+	s := float64(size)
+	rot := b.Rotation * math.Pi / 180
+	pts := make([]point, marbleSteps)
+
+	for i := range pts {
+		a := 2 * math.Pi * float64(i) / marbleSteps
+		x := b.Radius * s * math.Cos(a)
+		y := b.Radius * b.Stretch * s * math.Sin(a)
+
+		pts[i] = point{
+			X: b.X*s + x*math.Cos(rot) - y*math.Sin(rot),
+			Y: b.Y*s + x*math.Sin(rot) + y*math.Cos(rot),
+		}
+	}
+
+	return pts
+}
+
+// GenerateMarbleImage returns an anti-aliased image for the specified marble image description.
+func GenerateMarbleImage(m Marble, size int, p Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	colors := MarbleColors(p)
+	stops := make([]color.Color, 3)
+
+	// Convert the stops up front, as mixColors would otherwise convert them for every pixel.
+	for i, c := range colors[:3] {
+		stops[i] = color.NRGBAModel.Convert(c)
+	}
+
+	// Draw the gradient, which is rotated around the center of the image.
+	a := m.Angle * math.Pi / 180
+	cos, sin := math.Cos(a), math.Sin(a)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			u := (float64(x)+0.5)/float64(size) - 0.5
+			v := (float64(y)+0.5)/float64(size) - 0.5
+
+			// Set the pixel directly, as boxing each color to call Set is much slower.
+			r, g, b, a := gradientColor(stops, u*cos+v*sin+0.5).RGBA()
+			img.SetRGBA(x, y, color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)})
+		}
+	}
+
+	// Blur the blobs at a lower resolution if the image is large, as the cost of the blur grows with its area.
+	maskSize := size
+
+	if maskSize > marbleMaskSize {
+		maskSize = marbleMaskSize
+	}
+
+	sigma := marbleBlur * float64(maskSize)
+
+	// The mask extends past the edges of the image so that the parts of the blobs outside of it are still blurred
+	// into it.
+	r := image.Rect(0, 0, maskSize, maskSize).Inset(-int(math.Ceil(sigma * 3)))
+
+	for _, b := range m.Blobs {
+		blurred := blurMask(fillMask(r, [][][]point{{b.outline(maskSize)}}), sigma)
+		c := image.NewUniform(colors[b.Color%len(colors)])
+
+		var mask image.Image = blurred
+
+		if maskSize != size {
+			mask = scaledMask{mask: blurred, scale: float64(size) / float64(maskSize)}
+		}
+
+		draw.DrawMask(img, img.Rect, c, image.Point{}, mask, image.Point{}, draw.Over)
+	}
+
+	return img, nil
+}
+
+// GenerateMarbleSVG returns an SVG document for the specified marble image description, using native gradients and
+// blur filters.
+func GenerateMarbleSVG(m Marble, size int, p Palette) (string, error) {
+	if size <= 0 || size%8 != 0 {
+		return "", ErrInvalidSize
+	}
+
+	b := newSVGBuilder(size, nil, "")
+	colors := MarbleColors(p)
+
+	fmt.Fprintf(&b.buf, `<defs><linearGradient id="gradient" gradientTransform="rotate(%s 0.5 0.5)">`, svgNumber(m.Angle))
+
+	for i, c := range colors[:3] {
+		fmt.Fprintf(&b.buf, `<stop offset="%s" stop-color="%s"/>`, svgNumber(float64(i)/2), hexColor(c))
+	}
+
+	b.buf.WriteString(`</linearGradient><filter id="blur" x="-100%" y="-100%" width="300%" height="300%">`)
+	fmt.Fprintf(&b.buf, `<feGaussianBlur stdDeviation="%s"/></filter></defs>`, svgNumber(marbleBlur*float64(size)))
+	fmt.Fprintf(&b.buf, `<rect width="%d" height="%d" fill="url(#gradient)"/>`, size, size)
+
+	s := float64(size)
+
+	for _, blob := range m.Blobs {
+		cx, cy := svgNumber(blob.X*s), svgNumber(blob.Y*s)
+
+		fmt.Fprintf(&b.buf, `<ellipse cx="%s" cy="%s" rx="%s" ry="%s"`,
+			cx, cy, svgNumber(blob.Radius*s), svgNumber(blob.Radius*blob.Stretch*s))
+		fmt.Fprintf(&b.buf, ` transform="rotate(%s %s %s)" fill="%s" filter="url(#blur)"/>`,
+			svgNumber(blob.Rotation), cx, cy, hexColor(colors[blob.Color%len(colors)]))
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,184 @@
+package ppic_test
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+func TestGenerateMarble(t *testing.T) {
+	m := ppic.GenerateMarble("jackwilsdon")
+
+	if m != ppic.GenerateMarble("jackwilsdon") {
+		t.Error("expected the same description for the same key")
+	}
+
+	if m == ppic.GenerateMarble("example") {
+		t.Error("expected different descriptions for different keys")
+	}
+
+	if m.Angle < 0 || m.Angle >= 360 {
+		t.Errorf("expected angle to be between 0 and 360 but got %f", m.Angle)
+	}
+
+	for i, b := range m.Blobs {
+		if b.X < 0 || b.X > 1 || b.Y < 0 || b.Y > 1 {
+			t.Errorf("expected blob %d to be inside the image but got (%f, %f)", i, b.X, b.Y)
+		}
+
+		if b.Color < 3 || b.Color >= 5 {
+			t.Errorf("expected blob %d to use a blob color but got %d", i, b.Color)
+		}
+	}
+}
+
+func TestMarbleColors(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.White}
+	colors := ppic.MarbleColors(pal)
+
+	if len(colors) != 5 {
+		t.Fatalf("expected 5 colors but got %d", len(colors))
+	}
+
+	if !colorsEqual(colors[0], pal.Foreground) {
+		t.Errorf("expected first color to be %v but got %v", pal.Foreground, colors[0])
+	}
+
+	// Red rotated by 150 degrees is a blue-green.
+	if exp := (color.RGBA{G: 0xFF, B: 0x80, A: 0xFF}); !colorsEqual(colors[3], exp) {
+		t.Errorf("expected fourth color to be %v but got %v", exp, colors[3])
+	}
+}
+
+func TestGenerateMarbleImage(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.White}
+	colors := ppic.MarbleColors(pal)
+
+	cases := []struct {
+		name       string
+		angle      float64
+		start, end [2]int
+	}{
+		{"left-to-right", 0, [2]int{0, 32}, [2]int{63, 32}},
+		{"top-to-bottom", 90, [2]int{32, 0}, [2]int{32, 63}},
+		{"right-to-left", 180, [2]int{63, 32}, [2]int{0, 32}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			// Blobs without a size aren't drawn, leaving just the gradient.
+			img, err := ppic.GenerateMarbleImage(ppic.Marble{Angle: c.angle}, 64, pal)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, p := range []struct {
+				pt  [2]int
+				exp color.Color
+			}{
+				{c.start, colors[0]},
+				{[2]int{32, 32}, colors[1]},
+				{c.end, colors[2]},
+			} {
+				act := img.At(p.pt[0], p.pt[1])
+
+				if !colorsSimilar(act, p.exp, 0x10) {
+					t.Errorf("expected %v to be close to %v but got %v", p.pt, p.exp, act)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMarbleImageWithBlob(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.White}
+	colors := ppic.MarbleColors(pal)
+
+	m := ppic.Marble{}
+	m.Blobs[0] = ppic.MarbleBlob{X: 0.5, Y: 0.5, Radius: 0.4, Stretch: 1, Color: 3}
+
+	// Large images are blurred at a lower resolution and scaled up, which should look the same.
+	for _, size := range []int{256, 1024} {
+		img, err := ppic.GenerateMarbleImage(m, size, pal)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The middle of the blob should be solid, with the blur fading it out towards the edges.
+		if act := img.At(size/2, size/2); !colorsSimilar(act, colors[3], 0x04) {
+			t.Errorf("expected center of %d image to be %v but got %v", size, colors[3], act)
+		}
+
+		if act := img.At(0, 0); !colorsSimilar(act, colors[0], 0x04) {
+			t.Errorf("expected corner of %d image to be close to %v but got %v", size, colors[0], act)
+		}
+	}
+}
+
+func BenchmarkGenerateMarbleImage(b *testing.B) {
+	m := ppic.GenerateMarble("jackwilsdon")
+
+	for _, size := range []int{256, 2048} {
+		size := size
+
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := ppic.GenerateMarbleImage(m, size, ppic.DefaultPalette); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMarbleImageWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateMarbleImage(ppic.GenerateMarble("jackwilsdon"), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
+
+func TestGenerateMarbleSVG(t *testing.T) {
+	pal := ppic.GeneratePalette("jackwilsdon")
+	svg, err := ppic.GenerateMarbleSVG(ppic.GenerateMarble("jackwilsdon"), 64, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>") {
+		t.Errorf("expected an SVG document but got %q", svg)
+	}
+
+	for _, s := range []string{"<linearGradient", "<feGaussianBlur", `fill="url(#gradient)"`} {
+		if !strings.Contains(svg, s) {
+			t.Errorf("expected SVG to contain %q but got %q", s, svg)
+		}
+	}
+
+	if n := strings.Count(svg, "<ellipse"); n != 3 {
+		t.Errorf("expected 3 blobs but got %d", n)
+	}
+}
+
+func TestGenerateMarbleSVGWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateMarbleSVG(ppic.GenerateMarble("jackwilsdon"), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
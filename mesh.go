@@ -0,0 +1,338 @@
+package ppic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image/color"
+	"io"
+	"math"
+)
+
+// Vertex is a point in 3D space, with Z pointing up.
+type Vertex [3]float32
+
+// Triangle is a triangle made up of three vertices in counter-clockwise order (when viewed from outside the mesh).
+type Triangle [3]Vertex
+
+// Normal returns the unit normal of the triangle.
+func (t Triangle) Normal() Vertex {
+	ux, uy, uz := t[1][0]-t[0][0], t[1][1]-t[0][1], t[1][2]-t[0][2]
+	vx, vy, vz := t[2][0]-t[0][0], t[2][1]-t[0][1], t[2][2]-t[0][2]
+
+	n := Vertex{uy*vz - uz*vy, uz*vx - ux*vz, ux*vy - uy*vx}
+	l := float32(math.Sqrt(float64(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])))
+
+	if l == 0 {
+		return Vertex{}
+	}
+
+	return Vertex{n[0] / l, n[1] / l, n[2] / l}
+}
+
+// Mesh is a watertight triangle mesh generated from a grid.
+type Mesh struct {
+	// Base contains the triangles making up the base plate (rendered using the background color).
+	Base []Triangle
+
+	// Raised contains the triangles making up the extruded cells (rendered using the foreground color).
+	Raised []Triangle
+}
+
+// MeshOptions specifies the dimensions used when generating a mesh, in millimetres.
+type MeshOptions struct {
+	// CellSize is the width and depth of each cell in the grid.
+	CellSize float32
+
+	// BaseHeight is the height of the base plate.
+	BaseHeight float32
+
+	// Height is how far the foreground cells are extruded above the base plate.
+	Height float32
+}
+
+// DefaultMeshOptions are the default dimensions used when generating a mesh.
+var DefaultMeshOptions = MeshOptions{CellSize: 5, BaseHeight: 2, Height: 3}
+
+// meshBuilder accumulates the faces of a mesh.
+type meshBuilder struct {
+	mesh Mesh
+}
+
+// quad adds a quad to the mesh, flipping it if required so that it faces in the direction of the normal n.
+func (b *meshBuilder) quad(raised bool, n Vertex, v0, v1, v2, v3 Vertex) {
+	t := [2]Triangle{{v0, v1, v2}, {v0, v2, v3}}
+
+	// Reverse the winding order if the quad is facing the wrong way.
+	if tn := t[0].Normal(); tn[0]*n[0]+tn[1]*n[1]+tn[2]*n[2] < 0 {
+		t = [2]Triangle{{v0, v2, v1}, {v0, v3, v2}}
+	}
+
+	if raised {
+		b.mesh.Raised = append(b.mesh.Raised, t[0], t[1])
+	} else {
+		b.mesh.Base = append(b.mesh.Base, t[0], t[1])
+	}
+}
+
+// wall adds a vertical quad along the edge from (x0, y0) to (x1, y1), between the heights z0 and z1.
+func (b *meshBuilder) wall(raised bool, n Vertex, x0, y0, x1, y1, z0, z1 float32) {
+	b.quad(raised, n, Vertex{x0, y0, z0}, Vertex{x1, y1, z0}, Vertex{x1, y1, z1}, Vertex{x0, y0, z1})
+}
+
+// GenerateMesh returns a mesh for the specified grid, with the foreground cells extruded from a base plate.
+//
+// The top row of the grid is placed at the back of the mesh (the largest Y coordinate).
+func GenerateMesh(grid Grid, opts MeshOptions) Mesh {
+	b := meshBuilder{}
+
+	// height returns the height of a cell, or 0 if the cell is outside of the grid.
+	height := func(x, y int) float32 {
+		if x < 0 || y < 0 || x >= 8 || y >= 8 {
+			return 0
+		}
+
+		if grid[y][x] {
+			return opts.BaseHeight + opts.Height
+		}
+
+		return opts.BaseHeight
+	}
+
+	cs := opts.CellSize
+
+	for y, row := range grid {
+		for x, val := range row {
+			h := height(x, y)
+
+			// Work out the bounds of the cell (flipping the Y axis so that the first row is at the back).
+			x0, x1 := float32(x)*cs, float32(x+1)*cs
+			y0, y1 := float32(7-y)*cs, float32(8-y)*cs
+
+			// Add the top and bottom of the cell.
+			b.quad(val, Vertex{0, 0, 1}, Vertex{x0, y0, h}, Vertex{x1, y0, h}, Vertex{x1, y1, h}, Vertex{x0, y1, h})
+			b.quad(false, Vertex{0, 0, -1}, Vertex{x0, y0, 0}, Vertex{x1, y0, 0}, Vertex{x1, y1, 0}, Vertex{x0, y1, 0})
+
+			// Add walls on each side of the cell where the neighbouring cell is lower.
+			sides := []struct {
+				dx, dy             int
+				n                  Vertex
+				ex0, ey0, ex1, ey1 float32
+			}{
+				{-1, 0, Vertex{-1, 0, 0}, x0, y0, x0, y1},
+				{1, 0, Vertex{1, 0, 0}, x1, y0, x1, y1},
+				{0, -1, Vertex{0, 1, 0}, x0, y1, x1, y1},
+				{0, 1, Vertex{0, -1, 0}, x0, y0, x1, y0},
+			}
+
+			for _, s := range sides {
+				nh := height(x+s.dx, y+s.dy)
+
+				if nh >= h {
+					continue
+				}
+
+				// The part of the wall below the top of the base plate belongs to the base, so we split the wall
+				// there to avoid leaving a T-junction next to neighbouring outer walls.
+				if nh < opts.BaseHeight {
+					b.wall(false, s.n, s.ex0, s.ey0, s.ex1, s.ey1, nh, opts.BaseHeight)
+					nh = opts.BaseHeight
+				}
+
+				if nh < h {
+					b.wall(true, s.n, s.ex0, s.ey0, s.ex1, s.ey1, nh, h)
+				}
+			}
+		}
+	}
+
+	return b.mesh
+}
+
+// WriteSTL writes the mesh to w in binary STL format.
+func (m Mesh) WriteSTL(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	// Write the header (which must be 80 bytes and must not start with "solid").
+	header := [80]byte{}
+	copy(header[:], "go-ppic")
+
+	if _, err := bw.Write(header[:]); err != nil {
+		return err
+	}
+
+	if err := binary.Write(bw, binary.LittleEndian, uint32(len(m.Base)+len(m.Raised))); err != nil {
+		return err
+	}
+
+	for _, ts := range [][]Triangle{m.Base, m.Raised} {
+		for _, t := range ts {
+			// Each triangle is made up of its normal, its vertices and an (unused) attribute byte count.
+			record := struct {
+				Normal    Vertex
+				Triangle  Triangle
+				Attribute uint16
+			}{t.Normal(), t, 0}
+
+			if err := binary.Write(bw, binary.LittleEndian, record); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// glTF constants used when writing binary glTF files.
+const (
+	glbMagic        = 0x46546C67
+	glbVersion      = 2
+	glbChunkJSON    = 0x4E4F534A
+	glbChunkBIN     = 0x004E4942
+	gltfArrayBuffer = 34962
+	gltfFloat       = 5126
+)
+
+// gltfColor converts a color into a linear RGBA color factor.
+func gltfColor(c color.Color) [4]float64 {
+	n := toNRGBA(c)
+
+	return [4]float64{linearize(n.R), linearize(n.G), linearize(n.B), float64(n.A) / 0xFF}
+}
+
+// WriteGLB writes the mesh to w in binary glTF format, using the palette colors as materials.
+//
+// The mesh is converted to the glTF coordinate system (Y up, in metres).
+func (m Mesh) WriteGLB(w io.Writer, p Palette) error {
+	type object map[string]interface{}
+
+	bin := bytes.Buffer{}
+	primitives := []object{}
+	bufferViews := []object{}
+	accessors := []object{}
+
+	for i, ts := range [][]Triangle{m.Base, m.Raised} {
+		// Primitives must contain at least one vertex.
+		if len(ts) == 0 {
+			continue
+		}
+
+		positions := make([]float32, 0, len(ts)*9)
+		normals := make([]float32, 0, len(ts)*9)
+		lo := [3]float32{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+		hi := [3]float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+
+		for _, t := range ts {
+			n := t.Normal()
+
+			for _, v := range t {
+				// Convert from Z up to Y up, and from millimetres to metres.
+				g := [3]float32{v[0] / 1000, v[2] / 1000, -v[1] / 1000}
+
+				for j := range g {
+					lo[j] = float32(math.Min(float64(lo[j]), float64(g[j])))
+					hi[j] = float32(math.Max(float64(hi[j]), float64(g[j])))
+				}
+
+				positions = append(positions, g[0], g[1], g[2])
+				normals = append(normals, n[0], n[2], -n[1])
+			}
+		}
+
+		attributes := object{}
+
+		for _, a := range []struct {
+			name string
+			data []float32
+		}{{"POSITION", positions}, {"NORMAL", normals}} {
+			offset := bin.Len()
+
+			if err := binary.Write(&bin, binary.LittleEndian, a.data); err != nil {
+				return err
+			}
+
+			accessor := object{
+				"bufferView":    len(bufferViews),
+				"componentType": gltfFloat,
+				"count":         len(a.data) / 3,
+				"type":          "VEC3",
+			}
+
+			// Positions must specify their bounds.
+			if a.name == "POSITION" {
+				accessor["min"] = lo
+				accessor["max"] = hi
+			}
+
+			attributes[a.name] = len(accessors)
+			accessors = append(accessors, accessor)
+			bufferViews = append(bufferViews, object{
+				"buffer":     0,
+				"byteOffset": offset,
+				"byteLength": bin.Len() - offset,
+				"target":     gltfArrayBuffer,
+			})
+		}
+
+		primitives = append(primitives, object{"attributes": attributes, "material": i})
+	}
+
+	material := func(name string, c color.Color) object {
+		return object{
+			"name": name,
+			"pbrMetallicRoughness": object{
+				"baseColorFactor": gltfColor(c),
+				"metallicFactor":  0,
+				"roughnessFactor": 1,
+			},
+		}
+	}
+
+	doc := object{
+		"asset":       object{"version": "2.0", "generator": "go-ppic"},
+		"scene":       0,
+		"scenes":      []object{{"nodes": []int{0}}},
+		"nodes":       []object{{"mesh": 0}},
+		"meshes":      []object{{"primitives": primitives}},
+		"materials":   []object{material("background", p.Background), material("foreground", p.Foreground)},
+		"buffers":     []object{{"byteLength": bin.Len()}},
+		"bufferViews": bufferViews,
+		"accessors":   accessors,
+	}
+
+	js, err := json.Marshal(doc)
+
+	if err != nil {
+		return err
+	}
+
+	// Chunks must be aligned to 4 bytes (JSON is padded with spaces and binary data with zeros).
+	for len(js)%4 != 0 {
+		js = append(js, ' ')
+	}
+
+	for bin.Len()%4 != 0 {
+		bin.WriteByte(0)
+	}
+
+	header := []uint32{glbMagic, glbVersion, uint32(12 + 8 + len(js) + 8 + bin.Len())}
+
+	bw := bufio.NewWriter(w)
+
+	for _, v := range [][]uint32{header, {uint32(len(js)), glbChunkJSON}} {
+		if err := binary.Write(bw, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+
+	bw.Write(js)
+
+	if err := binary.Write(bw, binary.LittleEndian, []uint32{uint32(bin.Len()), glbChunkBIN}); err != nil {
+		return err
+	}
+
+	bw.Write(bin.Bytes())
+
+	return bw.Flush()
+}
@@ -0,0 +1,117 @@
+package ppic_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+	"github.com/jackwilsdon/go-ppic/ppictest"
+)
+
+var meshGrid = ppictest.Parse([8]string{
+	"# #  # #",
+	"# #### #",
+	"        ",
+	"# #  # #",
+	"  #  #  ",
+	"        ",
+	"##    ##",
+	"#      #",
+})
+
+func TestGenerateMeshIsWatertight(t *testing.T) {
+	m := ppic.GenerateMesh(meshGrid, ppic.DefaultMeshOptions)
+
+	// In a closed mesh with consistent winding, every directed edge is matched by an edge going the other way.
+	edges := make(map[[2]ppic.Vertex]int)
+
+	for _, ts := range [][]ppic.Triangle{m.Base, m.Raised} {
+		for _, tri := range ts {
+			for i := range tri {
+				edges[[2]ppic.Vertex{tri[i], tri[(i+1)%3]}]++
+			}
+		}
+	}
+
+	for e, n := range edges {
+		if r := edges[[2]ppic.Vertex{e[1], e[0]}]; r != n {
+			t.Fatalf("expected edge %v to be used %d times in reverse but got %d", e, n, r)
+		}
+	}
+}
+
+func TestGenerateMeshNormals(t *testing.T) {
+	m := ppic.GenerateMesh(meshGrid, ppic.DefaultMeshOptions)
+
+	// Every triangle in the raised part of the mesh should face up or sideways.
+	for _, tri := range m.Raised {
+		if n := tri.Normal(); n[2] < 0 {
+			t.Fatalf("expected raised triangle %v not to face down (normal %v)", tri, n)
+		}
+	}
+}
+
+func TestMeshWriteSTL(t *testing.T) {
+	m := ppic.GenerateMesh(meshGrid, ppic.DefaultMeshOptions)
+	buf := bytes.Buffer{}
+
+	if err := m.WriteSTL(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	n := len(m.Base) + len(m.Raised)
+
+	if l := buf.Len(); l != 84+50*n {
+		t.Errorf("expected STL to be %d bytes but got %d", 84+50*n, l)
+	}
+
+	if c := binary.LittleEndian.Uint32(buf.Bytes()[80:]); int(c) != n {
+		t.Errorf("expected STL to contain %d triangles but got %d", n, c)
+	}
+}
+
+func TestMeshWriteGLB(t *testing.T) {
+	m := ppic.GenerateMesh(meshGrid, ppic.DefaultMeshOptions)
+	buf := bytes.Buffer{}
+
+	if err := m.WriteGLB(&buf, ppic.DefaultPalette); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+
+	if magic := string(data[:4]); magic != "glTF" {
+		t.Fatalf("expected magic to be %q but got %q", "glTF", magic)
+	}
+
+	if l := binary.LittleEndian.Uint32(data[8:]); int(l) != len(data) {
+		t.Errorf("expected length to be %d but got %d", len(data), l)
+	}
+
+	jsonLen := binary.LittleEndian.Uint32(data[12:])
+
+	var doc struct {
+		Meshes []struct {
+			Primitives []struct {
+				Material int
+			}
+		}
+		Materials []struct {
+			Name string
+		}
+	}
+
+	if err := json.Unmarshal(data[20:20+jsonLen], &doc); err != nil {
+		t.Fatalf("failed to parse glTF JSON: %s", err)
+	}
+
+	if len(doc.Meshes) != 1 || len(doc.Meshes[0].Primitives) != 2 {
+		t.Fatalf("expected a single mesh with 2 primitives but got %+v", doc.Meshes)
+	}
+
+	if len(doc.Materials) != 2 {
+		t.Errorf("expected 2 materials but got %d", len(doc.Materials))
+	}
+}
@@ -0,0 +1,52 @@
+package ppic
+
+import "encoding/hex"
+
+// Metadata describes an image generated from a key, allowing it to be rendered without downloading the image.
+type Metadata struct {
+	// Key is the source text the image was generated from.
+	Key string `json:"key"`
+
+	// Hash is the hex encoded SHA256 hash of the key.
+	Hash string `json:"hash"`
+
+	// Seed is the random seed derived from the hash.
+	//
+	// The seed is encoded as a string in JSON, as most seeds are too large to be stored exactly in a JavaScript number.
+	Seed int64 `json:"seed,string"`
+
+	// MirrorX and MirrorY specify whether the grid was mirrored along the X and Y axes.
+	MirrorX bool `json:"mirrorX"`
+	MirrorY bool `json:"mirrorY"`
+
+	// Size is the width and height of the rendered image in pixels.
+	Size int `json:"size"`
+
+	// Grid is the generated grid, as rows of cells.
+	Grid Grid `json:"grid"`
+
+	// Palette is the palette used to render the grid.
+	Palette Palette `json:"palette"`
+
+	// Theme contains colors derived from the palette, for styling elements to match the image.
+	Theme Theme `json:"theme"`
+}
+
+// GenerateMetadata returns the metadata for the image generated from the provided source text and options.
+func GenerateMetadata(k string, mX, mY bool, size int, p Palette) (Metadata, error) {
+	if size <= 0 || size%8 != 0 {
+		return Metadata{}, ErrInvalidSize
+	}
+
+	return Metadata{
+		Key:     k,
+		Hash:    hex.EncodeToString(hashBytes(k)),
+		Seed:    hashString(k),
+		MirrorX: mX,
+		MirrorY: mY,
+		Size:    size,
+		Grid:    Generate(k, mX, mY),
+		Palette: p,
+		Theme:   GenerateTheme(p),
+	}, nil
+}
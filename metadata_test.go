@@ -0,0 +1,62 @@
+package ppic_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+func TestGenerateMetadata(t *testing.T) {
+	m, err := ppic.GenerateMetadata("jackwilsdon", true, false, 512, ppic.GeneratePalette("jackwilsdon"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := "532e4f1b9deae3a4271431af654d8a09c539046bad487f7ae6b18c1d0087d4d9"; m.Hash != exp {
+		t.Errorf("expected hash to be %q but got %q", exp, m.Hash)
+	}
+
+	if grid := ppic.Generate("jackwilsdon", true, false); m.Grid != grid {
+		t.Errorf("expected grid to be %v but got %v", grid, m.Grid)
+	}
+
+	buf, err := json.Marshal(m.Palette)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := `{"foreground":"#eae3a4","background":"#ffffff"}`; string(buf) != exp {
+		t.Errorf("expected palette to be %s but got %s", exp, buf)
+	}
+
+	// The seed is encoded as a string so it isn't rounded by JSON parsers which use floats.
+	buf, err = json.Marshal(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]interface{}
+
+	if err = json.Unmarshal(buf, &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := strconv.FormatInt(m.Seed, 10); fields["seed"] != exp {
+		t.Errorf("expected seed to be %q but got %v", exp, fields["seed"])
+	}
+}
+
+func TestGenerateMetadataWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateMetadata("jackwilsdon", true, false, size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package ppic
 
-import "image/color"
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+)
 
 // Palette represents a pair of colors to use in image generation.
 type Palette struct {
@@ -13,6 +17,46 @@ func (p Palette) Palette() color.Palette {
 	return color.Palette{p.Background, p.Foreground}
 }
 
+// MarshalJSON returns the palette as a JSON object containing hex color strings.
+func (p Palette) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Foreground string `json:"foreground"`
+		Background string `json:"background"`
+	}{
+		Foreground: hexColor(p.Foreground),
+		Background: hexColor(p.Background),
+	})
+}
+
+// overridePalette replaces the colors of a palette with any colors in a map ("fg" and "bg", parsed using ParseColor).
+func overridePalette(p Palette, colors map[string]string) (Palette, error) {
+	overrides := []struct {
+		name  string
+		color *color.Color
+	}{
+		{"fg", &p.Foreground},
+		{"bg", &p.Background},
+	}
+
+	for _, o := range overrides {
+		v, ok := colors[o.name]
+
+		if !ok {
+			continue
+		}
+
+		c, err := ParseColor(v)
+
+		if err != nil {
+			return Palette{}, fmt.Errorf("invalid %s color %q", o.name, v)
+		}
+
+		*o.color = c
+	}
+
+	return p, nil
+}
+
 // DefaultPalette is the default black and white color palette.
 var DefaultPalette = Palette{Foreground: color.Black, Background: color.White}
 
@@ -30,3 +74,21 @@ func GeneratePalette(k string) Palette {
 		Background: color.White,
 	}
 }
+
+// toNRGBA converts a color to a non-alpha-premultiplied color.NRGBA (which is what color.NRGBAModel always returns).
+func toNRGBA(c color.Color) color.NRGBA {
+	n, _ := color.NRGBAModel.Convert(c).(color.NRGBA)
+
+	return n
+}
+
+// hexColor returns the hex representation of a color (#RRGGBB, or #RRGGBBAA if it's not opaque).
+func hexColor(c color.Color) string {
+	n := toNRGBA(c)
+
+	if n.A == 0xFF {
+		return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
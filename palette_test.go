@@ -14,6 +14,22 @@ func colorsEqual(a, b color.Color) bool {
 	return aR == bR && aG == bG && aB == bB && aA == bA
 }
 
+// colorsSimilar returns whether each channel of two colors differs by at most d (out of 0xFF).
+func colorsSimilar(a, b color.Color, d uint8) bool {
+	na := color.NRGBAModel.Convert(a).(color.NRGBA)
+	nb := color.NRGBAModel.Convert(b).(color.NRGBA)
+
+	diff := func(x, y uint8) bool {
+		if x > y {
+			return x-y <= d
+		}
+
+		return y-x <= d
+	}
+
+	return diff(na.R, nb.R) && diff(na.G, nb.G) && diff(na.B, nb.B) && diff(na.A, nb.A)
+}
+
 func TestPalette(t *testing.T) {
 	p := ppic.Palette{Foreground: color.Black, Background: color.White}
 	pp := p.Palette()
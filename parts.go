@@ -0,0 +1,292 @@
+package ppic
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidPartSet is an error caused by specifying a part set which can't be used to generate images.
+var ErrInvalidPartSet = errors.New("invalid part set")
+
+// UnknownPartSetError is an error caused by specifying the name of a part set which hasn't been registered.
+type UnknownPartSetError struct {
+	Name string
+}
+
+// Error returns a description of the unknown part set.
+func (e *UnknownPartSetError) Error() string {
+	return fmt.Sprintf("unknown part set %q", e.Name)
+}
+
+// PartSet is a set of image layers which are combined to make up an avatar.
+//
+// One part is chosen from each layer (skipping any empty layers) and drawn on top of each other in the order body,
+// eyes, mouth and accessory. All of the parts in a set must be the same size.
+//
+// Parts are tinted using the palette; gray pixels are mapped onto a ramp from black through the foreground color to
+// white (so mid-gray becomes the foreground color), and any other colors are left as they are. Parts are scaled using
+// nearest-neighbour scaling, so they're best drawn as pixel art.
+type PartSet struct {
+	Body      []image.Image
+	Eyes      []image.Image
+	Mouth     []image.Image
+	Accessory []image.Image
+}
+
+// layers returns the layers of the set in the order they're drawn.
+func (s PartSet) layers() [][]image.Image {
+	return [][]image.Image{s.Body, s.Eyes, s.Mouth, s.Accessory}
+}
+
+// layerNames are the names of the directories each layer is loaded from (see LoadPartSet), in the same order as
+// PartSet.layers.
+var layerNames = []string{"body", "eyes", "mouth", "accessory"}
+
+// validate checks that the set contains at least one part and that all of the parts are the same size.
+func (s PartSet) validate() error {
+	var bounds *image.Rectangle
+
+	for _, layer := range s.layers() {
+		for _, part := range layer {
+			if part == nil {
+				return ErrInvalidPartSet
+			}
+
+			b := part.Bounds()
+
+			if bounds == nil {
+				bounds = &b
+			}
+
+			if b.Dx() != bounds.Dx() || b.Dy() != bounds.Dy() || b.Empty() {
+				return ErrInvalidPartSet
+			}
+		}
+	}
+
+	if bounds == nil {
+		return ErrInvalidPartSet
+	}
+
+	return nil
+}
+
+// partSets contains the registered part sets, indexed by name.
+//
+// The built-in robot parts are valid (which TestRobotParts checks), so they don't need to go through RegisterPartSet.
+var partSets = struct {
+	sync.RWMutex
+	sets map[string]PartSet
+}{sets: map[string]PartSet{"robot": RobotParts}}
+
+// RegisterPartSet registers a part set under the specified name, replacing any existing set with the same name.
+func RegisterPartSet(name string, s PartSet) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	partSets.Lock()
+	defer partSets.Unlock()
+
+	partSets.sets[strings.ToLower(name)] = s
+
+	return nil
+}
+
+// LookupPartSet returns the part set registered under the specified name.
+func LookupPartSet(name string) (PartSet, bool) {
+	partSets.RLock()
+	defer partSets.RUnlock()
+
+	s, ok := partSets.sets[strings.ToLower(name)]
+
+	return s, ok
+}
+
+// LoadPartSet loads a part set from a directory.
+//
+// The directory should contain "body", "eyes", "mouth" and "accessory" subdirectories, each containing the PNG images
+// for that layer (which are used in order of their file names). Missing subdirectories are treated as empty layers.
+func LoadPartSet(dir string) (PartSet, error) {
+	layers := make([][]image.Image, len(layerNames))
+
+	for i, name := range layerNames {
+		files, err := ioutil.ReadDir(filepath.Join(dir, name))
+
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return PartSet{}, err
+		}
+
+		// Files are returned sorted by name, but we sort them again to make the order explicit.
+		sort.Slice(files, func(a, b int) bool {
+			return files[a].Name() < files[b].Name()
+		})
+
+		for _, file := range files {
+			if file.IsDir() || strings.ToLower(filepath.Ext(file.Name())) != ".png" {
+				continue
+			}
+
+			img, err := loadPart(filepath.Join(dir, name, file.Name()))
+
+			if err != nil {
+				return PartSet{}, err
+			}
+
+			layers[i] = append(layers[i], img)
+		}
+	}
+
+	s := PartSet{Body: layers[0], Eyes: layers[1], Mouth: layers[2], Accessory: layers[3]}
+
+	if err := s.validate(); err != nil {
+		return PartSet{}, fmt.Errorf("%s: %s", dir, err)
+	}
+
+	return s, nil
+}
+
+// loadPart decodes a single part from a file.
+func loadPart(path string) (image.Image, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return img, nil
+}
+
+// RegisterPartSetDir loads a part set from a directory (see LoadPartSet) and registers it under the specified name.
+func RegisterPartSetDir(name, dir string) error {
+	s, err := LoadPartSet(dir)
+
+	if err != nil {
+		return err
+	}
+
+	return RegisterPartSet(name, s)
+}
+
+// GenerateParts returns the parts chosen for the provided source text, in the order they should be drawn.
+func GenerateParts(k string, s PartSet) []image.Image {
+	hsh := hashBytes(k)
+	parts := make([]image.Image, 0, len(layerNames))
+
+	for i, layer := range s.layers() {
+		if len(layer) == 0 {
+			continue
+		}
+
+		// Each layer uses 2 bytes of the hash, so that large layers are still chosen evenly.
+		n := int(hsh[i*2])<<8 | int(hsh[i*2+1])
+		parts = append(parts, layer[n%len(layer)])
+	}
+
+	return parts
+}
+
+// tintPart returns the color of a part's pixel after it's been tinted using the foreground color.
+func tintPart(c color.Color, fg color.Color) color.NRGBA {
+	n := toNRGBA(c)
+
+	// Only gray pixels are tinted.
+	if n.R != n.G || n.G != n.B {
+		return n
+	}
+
+	var t color.NRGBA
+
+	// 0x80 is treated as the middle of the ramp so that it maps exactly onto the foreground color.
+	if v := float64(n.R); v <= 0x80 {
+		t = mixColors(color.Black, fg, v/0x80)
+	} else {
+		t = mixColors(fg, color.White, (v-0x80)/0x7F)
+	}
+
+	t.A = n.A
+
+	return t
+}
+
+// partsTint returns the color used to tint parts.
+//
+// Very dark and very light foreground colors are moved towards the middle of the ramp, as otherwise the outlines or
+// highlights of the parts would disappear (such as when using DefaultPalette).
+func partsTint(fg color.Color) color.Color {
+	switch l := relativeLuminance(fg); {
+	case l < 0.05:
+		return mixColors(fg, color.White, 0.5)
+	case l > 0.8:
+		return mixColors(fg, color.Black, 0.5)
+	default:
+		return fg
+	}
+}
+
+// GeneratePartsImage returns an image made up of the provided parts, tinted using the palette and drawn on top of
+// the background color.
+func GeneratePartsImage(parts []image.Image, size int, p Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Rect, image.NewUniform(p.Background), image.Point{}, draw.Src)
+
+	if len(parts) == 0 {
+		return img, nil
+	}
+
+	// Combine the tinted parts at their original size.
+	tint := partsTint(p.Foreground)
+	b := parts[0].Bounds()
+	canvas := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+
+	for _, part := range parts {
+		pb := part.Bounds()
+		tinted := image.NewNRGBA(canvas.Rect)
+
+		for y := 0; y < pb.Dy(); y++ {
+			for x := 0; x < pb.Dx(); x++ {
+				tinted.SetNRGBA(x, y, tintPart(part.At(pb.Min.X+x, pb.Min.Y+y), tint))
+			}
+		}
+
+		draw.Draw(canvas, canvas.Rect, tinted, image.Point{}, draw.Over)
+	}
+
+	// Scale the parts up to the size of the image using nearest-neighbour scaling.
+	scaled := image.NewNRGBA(img.Rect)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			scaled.SetNRGBA(x, y, canvas.NRGBAAt(x*b.Dx()/size, y*b.Dy()/size))
+		}
+	}
+
+	draw.Draw(img, img.Rect, scaled, image.Point{}, draw.Over)
+
+	return img, nil
+}
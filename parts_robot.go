@@ -0,0 +1,199 @@
+package ppic
+
+import (
+	"image"
+	"image/color"
+)
+
+// robotSize is the width and height of the built-in robot parts.
+const robotSize = 16
+
+// robotColors maps the characters used to draw the built-in robot parts to colors.
+//
+// Spaces are transparent, and the grays are tinted using the palette (see PartSet).
+var robotColors = map[rune]color.Color{
+	'k': color.Gray{Y: 0x00},
+	'd': color.Gray{Y: 0x40},
+	'#': color.Gray{Y: 0x80},
+	'l': color.Gray{Y: 0xC0},
+	'w': color.Gray{Y: 0xFF},
+	'r': color.RGBA{R: 0xE0, G: 0x40, B: 0x40, A: 0xFF},
+	'y': color.RGBA{R: 0xF5, G: 0xC5, B: 0x18, A: 0xFF},
+}
+
+// pixelArt returns a robotSize by robotSize part, with the rows drawn starting at (x, y).
+func pixelArt(x, y int, rows ...string) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, robotSize, robotSize))
+
+	for rY, row := range rows {
+		for rX, c := range []rune(row) {
+			if c == ' ' {
+				continue
+			}
+
+			img.Set(x+rX, y+rY, robotColors[c])
+		}
+	}
+
+	return img
+}
+
+// RobotParts is the built-in part set, registered as "robot".
+var RobotParts = PartSet{
+	Body: []image.Image{
+		// Square head.
+		pixelArt(2, 3,
+			"kkkkkkkkkkkk",
+			"klllllllll#k",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"k#dddddddddk",
+			"kkkkkkkkkkkk",
+		),
+		// Rounded head.
+		pixelArt(2, 3,
+			" kkkkkkkkkk ",
+			"klllllllll#k",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"k#dddddddddk",
+			" kkkkkkkkkk ",
+		),
+		// Domed head.
+		pixelArt(2, 3,
+			"   kkkkkk   ",
+			" kkllllll#kk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"k#dddddddddk",
+			"kkkkkkkkkkkk",
+		),
+		// Tapered head.
+		pixelArt(2, 3,
+			"  kkkkkkkk  ",
+			"  klllll#k  ",
+			" kl######dk ",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"kl########dk",
+			"k#dddddddddk",
+			"kkkkkkkkkkkk",
+		),
+	},
+	Eyes: []image.Image{
+		// Round eyes.
+		pixelArt(3, 6,
+			" kk    kk ",
+			"kwwk  kwwk",
+			"kwkk  kwkk",
+			" kk    kk ",
+		),
+		// Visor.
+		pixelArt(3, 6,
+			"kkkkkkkkkk",
+			"krrrrrrrrk",
+			"kkkkkkkkkk",
+		),
+		// Glowing eyes.
+		pixelArt(3, 6,
+			"kkk    kkk",
+			"kyk    kyk",
+			"kkk    kkk",
+		),
+		// Happy eyes.
+		pixelArt(3, 6,
+			" kk    kk ",
+			"k  k  k  k",
+		),
+		// Single eye.
+		pixelArt(5, 5,
+			" kkkk ",
+			"kwwwwk",
+			"kwkkwk",
+			"kwwwwk",
+			" kkkk ",
+		),
+	},
+	Mouth: []image.Image{
+		// Teeth.
+		pixelArt(4, 11,
+			"kkkkkkkk",
+			"kwkwwkwk",
+			"kkkkkkkk",
+		),
+		// Smile.
+		pixelArt(4, 11,
+			"k      k",
+			" kkkkkk ",
+		),
+		// Open mouth.
+		pixelArt(4, 11,
+			" kkkkkk ",
+			"krrrrrrk",
+			" kkkkkk ",
+		),
+		// Flat mouth.
+		pixelArt(4, 12,
+			"kkkkkkkk",
+		),
+		// Zigzag.
+		pixelArt(4, 11,
+			"k  kk  k",
+			" kk  kk ",
+		),
+	},
+	Accessory: []image.Image{
+		// Nothing.
+		pixelArt(0, 0),
+		// Antenna.
+		pixelArt(7, 0,
+			"yy",
+			"kk",
+			"kk",
+		),
+		// Ears.
+		pixelArt(0, 7,
+			"kk            kk",
+			"k#            #k",
+			"kk            kk",
+		),
+		// Horns.
+		pixelArt(2, 0,
+			"k          k",
+			"kk        kk",
+			" kk      kk ",
+		),
+		// Twin antennas.
+		pixelArt(4, 0,
+			"y      y",
+			"k      k",
+			"k      k",
+		),
+	},
+}
@@ -0,0 +1,231 @@
+package ppic_test
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+// writePart writes a part filled with a single color to a PNG file.
+func writePart(t *testing.T, path string, w, h int, c color.Color) {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Create(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRobotParts(t *testing.T) {
+	set, ok := ppic.LookupPartSet("robot")
+
+	if !ok {
+		t.Fatal("expected robot part set to be registered")
+	}
+
+	for name, layer := range map[string][]image.Image{
+		"body":      set.Body,
+		"eyes":      set.Eyes,
+		"mouth":     set.Mouth,
+		"accessory": set.Accessory,
+	} {
+		if len(layer) == 0 {
+			t.Errorf("expected %s layer to contain parts", name)
+		}
+	}
+
+	// The robot parts are registered without being validated.
+	if err := ppic.RegisterPartSet("robot", ppic.RobotParts); err != nil {
+		t.Errorf("expected robot part set to be valid but got %q", err)
+	}
+}
+
+func TestGenerateParts(t *testing.T) {
+	a := ppic.GenerateParts("jackwilsdon", ppic.RobotParts)
+	b := ppic.GenerateParts("jackwilsdon", ppic.RobotParts)
+
+	if len(a) != 4 {
+		t.Fatalf("expected 4 parts but got %d", len(a))
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("expected part %d to be the same for the same key", i)
+		}
+	}
+
+	// Empty layers are skipped.
+	set := ppic.PartSet{Body: ppic.RobotParts.Body}
+
+	if parts := ppic.GenerateParts("jackwilsdon", set); len(parts) != 1 {
+		t.Errorf("expected 1 part but got %d", len(parts))
+	}
+}
+
+func TestGeneratePartsImage(t *testing.T) {
+	part := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	part.Set(0, 0, color.Gray{Y: 0x80})
+	part.Set(1, 0, color.Gray{Y: 0xFF})
+	part.Set(0, 1, color.RGBA{R: 0xFF, A: 0xFF})
+
+	pal := ppic.Palette{Foreground: color.RGBA{B: 0xFF, A: 0xFF}, Background: color.RGBA{G: 0xFF, A: 0xFF}}
+	img, err := ppic.GeneratePartsImage([]image.Image{part}, 64, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		x, y int
+		exp  color.Color
+	}{
+		// Mid-gray becomes the foreground color and white stays white.
+		{0, 0, pal.Foreground},
+		{31, 31, pal.Foreground},
+		{32, 0, color.White},
+		// Other colors are left as they are.
+		{0, 32, color.RGBA{R: 0xFF, A: 0xFF}},
+		// Transparent pixels show the background.
+		{32, 32, pal.Background},
+		{63, 63, pal.Background},
+	} {
+		if act := img.At(c.x, c.y); !colorsEqual(act, c.exp) {
+			t.Errorf("expected (%d, %d) to be %v but got %v", c.x, c.y, c.exp, act)
+		}
+	}
+}
+
+func TestGeneratePartsImageWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GeneratePartsImage(ppic.GenerateParts("jackwilsdon", ppic.RobotParts), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
+
+func TestRegisterPartSetWithInvalidSet(t *testing.T) {
+	cases := []struct {
+		name string
+		set  ppic.PartSet
+	}{
+		{"empty", ppic.PartSet{}},
+		{"mismatched", ppic.PartSet{
+			Body: []image.Image{image.NewNRGBA(image.Rect(0, 0, 16, 16))},
+			Eyes: []image.Image{image.NewNRGBA(image.Rect(0, 0, 8, 8))},
+		}},
+		{"nil", ppic.PartSet{Body: []image.Image{nil}}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			if err := ppic.RegisterPartSet("invalid", c.set); err != ppic.ErrInvalidPartSet {
+				t.Errorf("expected error to be %q but got %v", ppic.ErrInvalidPartSet, err)
+			}
+
+			if _, ok := ppic.LookupPartSet("invalid"); ok {
+				t.Error("expected invalid part set not to be registered")
+			}
+		})
+	}
+}
+
+func TestRegisterPartSetDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ppic")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	writePart(t, filepath.Join(dir, "body", "b.png"), 4, 4, color.Gray{Y: 0x80})
+	writePart(t, filepath.Join(dir, "body", "a.png"), 4, 4, color.RGBA{R: 0xFF, A: 0xFF})
+	writePart(t, filepath.Join(dir, "eyes", "a.png"), 4, 4, color.Transparent)
+
+	// Files which aren't PNG images are ignored.
+	if err := ioutil.WriteFile(filepath.Join(dir, "body", "README"), []byte("parts"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ppic.RegisterPartSetDir("test", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	set, ok := ppic.LookupPartSet("TEST")
+
+	if !ok {
+		t.Fatal("expected test part set to be registered")
+	}
+
+	if len(set.Body) != 2 || len(set.Eyes) != 1 || len(set.Mouth) != 0 || len(set.Accessory) != 0 {
+		t.Fatalf("expected 2 body, 1 eyes, 0 mouth and 0 accessory parts but got %d, %d, %d and %d",
+			len(set.Body), len(set.Eyes), len(set.Mouth), len(set.Accessory))
+	}
+
+	// Parts are sorted by file name.
+	if act := set.Body[0].At(0, 0); !colorsEqual(act, color.RGBA{R: 0xFF, A: 0xFF}) {
+		t.Errorf("expected first body part to be a.png but got color %v", act)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/example?style=parts&set=test", nil)
+
+	if err != nil {
+		t.Fatalf("http.NewRequest: %s", err)
+	}
+
+	rec := httptest.NewRecorder()
+
+	ppic.Handler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status to be %d but got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestLoadPartSetWithMismatchedSizes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ppic")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	writePart(t, filepath.Join(dir, "body", "a.png"), 4, 4, color.Black)
+	writePart(t, filepath.Join(dir, "mouth", "a.png"), 8, 8, color.Black)
+
+	if _, err := ppic.LoadPartSet(dir); err == nil {
+		t.Error("expected an error but got nil")
+	}
+}
@@ -0,0 +1,311 @@
+package ppic
+
+import (
+	"bufio"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"io"
+	"sync"
+)
+
+// pngSignature is the signature at the start of every PNG file.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+const (
+	// pngColorTypePaletted is the PNG color type for images using a palette.
+	pngColorTypePaletted = 3
+
+	// pngFilterUp is the PNG filter type which stores the difference from the row above.
+	pngFilterUp = 2
+
+	// pngChunkSize is the maximum size of each IDAT chunk.
+	pngChunkSize = 1 << 15
+)
+
+// pngWriter writes PNG chunks, keeping track of the first error which occurs.
+type pngWriter struct {
+	w   io.Writer
+	err error
+}
+
+// writeChunk writes a chunk with the specified type and data.
+func (pw *pngWriter) writeChunk(typ string, data []byte) {
+	if pw.err != nil {
+		return
+	}
+
+	var header [8]byte
+
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], typ)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+
+	var footer [4]byte
+
+	binary.BigEndian.PutUint32(footer[:], crc.Sum32())
+
+	for _, b := range [][]byte{header[:], data, footer[:]} {
+		if _, pw.err = pw.w.Write(b); pw.err != nil {
+			return
+		}
+	}
+}
+
+// Write writes the data as an IDAT chunk, allowing compressed image data to be written straight to the PNG.
+func (pw *pngWriter) Write(p []byte) (int, error) {
+	pw.writeChunk("IDAT", p)
+
+	if pw.err != nil {
+		return 0, pw.err
+	}
+
+	return len(p), nil
+}
+
+// zlibWriters contains a pool of zlib writers for each compression level, as creating them is expensive.
+var zlibWriters = newZlibWriterPools()
+
+// newZlibWriterPools returns a pool of zlib writers for each of the PNG compression levels.
+func newZlibWriterPools() map[int]*sync.Pool {
+	pools := make(map[int]*sync.Pool, len(pngCompressionLevels))
+
+	for _, level := range pngCompressionLevels {
+		level := level
+
+		pools[level] = &sync.Pool{
+			New: func() interface{} {
+				zw, _ := zlib.NewWriterLevel(nil, level)
+
+				return zw
+			},
+		}
+	}
+
+	return pools
+}
+
+// getZlibWriter returns a zlib writer for the specified compression level which writes to w, along with a function to
+// return it to the pool once it has been closed.
+func getZlibWriter(w io.Writer, level int) (*zlib.Writer, func(), error) {
+	var zw *zlib.Writer
+
+	pool, ok := zlibWriters[level]
+
+	if ok {
+		zw, _ = pool.Get().(*zlib.Writer)
+	}
+
+	// Levels without a pool (and pools which failed to create a writer) get a new writer which isn't reused.
+	if zw == nil {
+		nw, err := zlib.NewWriterLevel(w, level)
+
+		return nw, func() {}, err
+	}
+
+	zw.Reset(w)
+
+	return zw, func() { pool.Put(zw) }, nil
+}
+
+// pngBitDepth returns the smallest bit depth which can index a palette with n colors.
+func pngBitDepth(n int) uint {
+	switch {
+	case n <= 2:
+		return 1
+	case n <= 4:
+		return 2
+	case n <= 16:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// pngRowFunc writes the palette indices of row y of an image into dst, returning false if dst was left unchanged.
+type pngRowFunc func(y int, dst []uint8) bool
+
+// writePalettedPNG writes a paletted PNG image using the smallest bit depth which fits the palette, with each row
+// filtered using the row above (which makes repeated rows almost free to compress).
+//
+// The palette indices of each row are written into dst by row, which means the image never needs to exist in memory
+// all at once. dst still contains the previous row, and row returns false if it was left unchanged (which skips
+// packing it again). The palette must contain between 1 and 256 colors.
+func writePalettedPNG(w io.Writer, width, height int, pal color.Palette, level int, row pngRowFunc) error {
+	depth := pngBitDepth(len(pal))
+	pw := &pngWriter{w: w}
+
+	if _, err := io.WriteString(w, pngSignature); err != nil {
+		return err
+	}
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(width))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(height))
+	ihdr[8] = uint8(depth)
+	ihdr[9] = pngColorTypePaletted
+	pw.writeChunk("IHDR", ihdr)
+
+	plte := make([]byte, 0, len(pal)*3)
+	trns := make([]byte, 0, len(pal))
+	opaque := true
+
+	for _, c := range pal {
+		n := toNRGBA(c)
+		plte = append(plte, n.R, n.G, n.B)
+		trns = append(trns, n.A)
+
+		if n.A != 0xFF {
+			opaque = false
+		}
+	}
+
+	pw.writeChunk("PLTE", plte)
+
+	// Transparency only needs to be written if one of the colors isn't opaque.
+	if !opaque {
+		pw.writeChunk("tRNS", trns)
+	}
+
+	if pw.err != nil {
+		return pw.err
+	}
+
+	bw := bufio.NewWriterSize(pw, pngChunkSize)
+	zw, release, err := getZlibWriter(bw, level)
+
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	// Each row starts with the filter type, followed by the packed palette indices.
+	stride := (width*int(depth) + 7) / 8
+	indices := make([]uint8, width)
+	prev := make([]byte, stride)
+	cur := make([]byte, stride)
+	out := make([]byte, stride+1)
+	out[0] = pngFilterUp
+	perByte := 8 / int(depth)
+
+	for y := 0; y < height; y++ {
+		if row(y, indices) {
+			for i := range cur {
+				var b byte
+
+				for x := i * perByte; x < (i+1)*perByte; x++ {
+					b <<= depth
+
+					if x < width {
+						b |= indices[x]
+					}
+				}
+
+				cur[i] = b
+			}
+		} else {
+			copy(cur, prev)
+		}
+
+		for i, b := range cur {
+			out[i+1] = b - prev[i]
+		}
+
+		if _, err = zw.Write(out); err != nil {
+			return err
+		}
+
+		prev, cur = cur, prev
+	}
+
+	if err = zw.Close(); err != nil {
+		return err
+	}
+
+	if err = bw.Flush(); err != nil {
+		return err
+	}
+
+	pw.writeChunk("IEND", nil)
+
+	return pw.err
+}
+
+// writePalettedImagePNG writes a paletted image as a PNG using writePalettedPNG.
+func writePalettedImagePNG(w io.Writer, img *image.Paletted, level int) error {
+	b := img.Rect
+
+	return writePalettedPNG(w, b.Dx(), b.Dy(), img.Palette, level, func(y int, dst []uint8) bool {
+		i := img.PixOffset(b.Min.X, b.Min.Y+y)
+
+		copy(dst, img.Pix[i:i+b.Dx()])
+
+		return true
+	})
+}
+
+// writePalettedImageRowsPNG writes any paletted image as a PNG using writePalettedPNG, reading it one row at a time.
+func writePalettedImageRowsPNG(w io.Writer, img image.PalettedImage, pal color.Palette, level int) error {
+	b := img.Bounds()
+
+	return writePalettedPNG(w, b.Dx(), b.Dy(), pal, level, func(y int, dst []uint8) bool {
+		for x := range dst {
+			dst[x] = img.ColorIndexAt(b.Min.X+x, b.Min.Y+y)
+		}
+
+		return true
+	})
+}
+
+// writeGridImagePNG writes a grid image as a PNG using writePalettedPNG, generating each row directly from the grid.
+func writeGridImagePNG(w io.Writer, img *GridImage, level int) error {
+	pSize := img.size / 8
+
+	return writePalettedPNG(w, img.size, img.size, img.palette, level, func(y int, dst []uint8) bool {
+		if y%pSize != 0 {
+			// Every row in a grid cell is the same, so the previous row can be reused.
+			return false
+		}
+
+		for x, val := range img.grid[y/pSize] {
+			var c uint8
+
+			if val {
+				c = 1
+			}
+
+			for i := x * pSize; i < (x+1)*pSize; i++ {
+				dst[i] = c
+			}
+		}
+
+		return true
+	})
+}
+
+// EncodeGridPNG writes a grid directly as a PNG, generating each row of the image from the grid instead of drawing the
+// whole image first (which is much faster than encoding the result of GenerateImage).
+//
+// The "compression" option is supported in the same way as the PNG encoder, and the size must be a positive multiple
+// of 8.
+func EncodeGridPNG(w io.Writer, grid Grid, size int, p Palette, opts EncodeOptions) error {
+	img, err := NewGridImage(grid, size, p)
+
+	if err != nil {
+		return err
+	}
+
+	level, err := pngCompressionLevel(opts)
+
+	if err != nil {
+		return err
+	}
+
+	return writeGridImagePNG(w, img, level)
+}
@@ -0,0 +1,267 @@
+package ppic_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+	"github.com/jackwilsdon/go-ppic/ppictest"
+)
+
+var pngGrid = [8]string{
+	"# #  # #",
+	"# #### #",
+	"        ",
+	"# #  # #",
+	"  #  #  ",
+	"        ",
+	"##    ##",
+	"#      #",
+}
+
+func BenchmarkEncodePNG(b *testing.B) {
+	grid := ppictest.Parse(pngGrid)
+	enc, _ := ppic.LookupEncoder("png")
+
+	// The standard library encoders are what the handler used previously.
+	for _, level := range []png.CompressionLevel{png.NoCompression, png.DefaultCompression} {
+		level := level
+		name := "StandardLibrary"
+
+		if level == png.DefaultCompression {
+			name += "Compressed"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			stdlib := png.Encoder{CompressionLevel: level}
+
+			for n := 0; n < b.N; n++ {
+				img, err := ppic.GenerateImage(grid, 512, ppic.DefaultPalette)
+
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				if err = stdlib.Encode(&bytes.Buffer{}, img); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+
+	b.Run("Paletted", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			img, err := ppic.GenerateImage(grid, 512, ppic.DefaultPalette)
+
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if err = enc.Encode(&bytes.Buffer{}, img, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Grid", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if err := ppic.EncodeGridPNG(&bytes.Buffer{}, grid, 512, ppic.DefaultPalette, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkEncodeLargePNG(b *testing.B) {
+	grid := ppictest.Parse(pngGrid)
+	enc, _ := ppic.LookupEncoder("png")
+
+	generators := []struct {
+		name     string
+		generate func() (image.Image, error)
+	}{
+		{"GenerateImage", func() (image.Image, error) { return ppic.GenerateImage(grid, 8192, ppic.DefaultPalette) }},
+		{"GridImage", func() (image.Image, error) { return ppic.NewGridImage(grid, 8192, ppic.DefaultPalette) }},
+	}
+
+	for _, g := range generators {
+		g := g
+
+		b.Run(g.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for n := 0; n < b.N; n++ {
+				img, err := g.generate()
+
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				if err = enc.Encode(&bytes.Buffer{}, img, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// comparePNG decodes a PNG and checks that it matches an image.
+func comparePNG(data []byte, expected image.Image) error {
+	img, err := png.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return err
+	}
+
+	b := expected.Bounds()
+
+	if img.Bounds().Dx() != b.Dx() || img.Bounds().Dy() != b.Dy() {
+		return fmt.Errorf("expected size %dx%d but got %dx%d", b.Dx(), b.Dy(), img.Bounds().Dx(), img.Bounds().Dy())
+	}
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			e := color.NRGBAModel.Convert(expected.At(b.Min.X+x, b.Min.Y+y))
+			a := color.NRGBAModel.Convert(img.At(img.Bounds().Min.X+x, img.Bounds().Min.Y+y))
+
+			if e != a {
+				return fmt.Errorf("expected %v at (%d, %d) but got %v", e, x, y, a)
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestEncodePNGPaletted(t *testing.T) {
+	enc, _ := ppic.LookupEncoder("png")
+
+	for _, n := range []int{1, 2, 3, 4, 5, 16, 17, 256} {
+		n := n
+
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.T) {
+			pal := make(color.Palette, n)
+
+			for i := range pal {
+				pal[i] = color.NRGBA{R: uint8(i), G: uint8(i * 3), B: uint8(i * 7), A: uint8(0xFF - i%2)}
+			}
+
+			// Use an odd size and offset so that rows don't line up with bytes.
+			img := image.NewPaletted(image.Rect(3, 5, 16, 15), pal)
+
+			for i := range img.Pix {
+				img.Pix[i] = uint8((i * 7) % n)
+			}
+
+			buf := bytes.Buffer{}
+
+			if err := enc.Encode(&buf, img, ppic.EncodeOptions{"compression": "best"}); err != nil {
+				t.Fatalf("failed to encode image: %s", err)
+			}
+
+			if err := comparePNG(buf.Bytes(), img); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestEncodeGridPNG(t *testing.T) {
+	grid := ppictest.Parse(pngGrid)
+	p := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.Transparent}
+
+	for _, size := range []int{8, 24, 64} {
+		size := size
+
+		t.Run(fmt.Sprintf("%d", size), func(t *testing.T) {
+			buf := bytes.Buffer{}
+
+			if err := ppic.EncodeGridPNG(&buf, grid, size, p, nil); err != nil {
+				t.Fatalf("failed to encode grid: %s", err)
+			}
+
+			expected, err := ppic.GenerateImage(grid, size, p)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := comparePNG(buf.Bytes(), expected); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	if err := ppic.EncodeGridPNG(&bytes.Buffer{}, grid, 12, p, nil); err != ppic.ErrInvalidSize {
+		t.Errorf("expected %q but got %v", ppic.ErrInvalidSize, err)
+	}
+
+	if err := ppic.EncodeGridPNG(&bytes.Buffer{}, grid, 0, p, nil); err != ppic.ErrInvalidSize {
+		t.Errorf("expected %q but got %v", ppic.ErrInvalidSize, err)
+	}
+
+	if err := ppic.EncodeGridPNG(&bytes.Buffer{}, grid, 8, p, ppic.EncodeOptions{"compression": "foo"}); err == nil {
+		t.Error("expected invalid compression to return an error")
+	}
+}
+
+// palettedImage hides the type of an image, so that it isn't encoded as an *image.Paletted.
+type palettedImage struct {
+	image.PalettedImage
+}
+
+func TestEncodePNGPalettedImage(t *testing.T) {
+	enc, _ := ppic.LookupEncoder("png")
+	grid := ppictest.Parse(pngGrid)
+	p := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.White}
+
+	img, err := ppic.NewGridImage(grid, 64, p)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []image.Image{img, palettedImage{img}} {
+		buf := bytes.Buffer{}
+
+		if err = enc.Encode(&buf, i, nil); err != nil {
+			t.Fatalf("failed to encode image: %s", err)
+		}
+
+		if err = comparePNG(buf.Bytes(), img); err != nil {
+			t.Errorf("%T: %s", i, err)
+		}
+	}
+}
+
+func TestEncodeGIFGridImage(t *testing.T) {
+	enc, _ := ppic.LookupEncoder("gif")
+	p := ppic.Palette{Foreground: color.RGBA{R: 0x12, G: 0x34, B: 0x56, A: 0xFF}, Background: color.White}
+	img, err := ppic.NewGridImage(ppictest.Parse(pngGrid), 64, p)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := bytes.Buffer{}
+
+	if err = enc.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("failed to encode image: %s", err)
+	}
+
+	decoded, err := gif.Decode(&buf)
+
+	if err != nil {
+		t.Fatalf("failed to decode image: %s", err)
+	}
+
+	// The colors should be kept exactly, rather than being quantized.
+	if err = ppictest.CompareImage(decoded, p, pngGrid); err != nil {
+		t.Error(err)
+	}
+}
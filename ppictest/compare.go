@@ -25,17 +25,9 @@ func validateExpected(expected [8]string) {
 // Compare an 8x8 grid to an expected image.
 //
 // Expected image must be 8 lines, each consisting of 8 characters.
-func Compare(grid [8][8]bool, expected [8]string) error {
-	validateExpected(expected)
-
-	for y := range grid {
-		for x := range grid[y] {
-			exp := expected[y][x] == '#'
-
-			if act := grid[y][x]; act != exp {
-				return fmt.Errorf("expected grid[%d][%d] to be %t but got %t", y, x, exp, act)
-			}
-		}
+func Compare(grid ppic.Grid, expected [8]string) error {
+	if exp := Parse(expected); grid != exp {
+		return fmt.Errorf("expected grid to be\n%s\nbut got\n%s", exp, grid)
 	}
 
 	return nil
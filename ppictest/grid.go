@@ -1,21 +1,22 @@
 package ppictest
 
-import "fmt"
+import (
+	"strings"
 
-func Parse(source [8]string) (grid [8][8]bool) {
-	for y, row := range source {
-		if l := len(row); l != 8 {
-			panic(fmt.Sprintf("len(grid[%d]) != 8 (got %d)", y, l))
-		}
+	"github.com/jackwilsdon/go-ppic"
+)
 
-		for x, c := range row {
-			if c != '#' && c != ' ' {
-				panic(fmt.Sprintf("grid[%d][%d] is not '#' or ' ' (got %q)", y, x, c))
-			}
+// Parse an 8x8 grid from an image.
+//
+// Image must be 8 lines, each consisting of 8 characters.
+func Parse(source [8]string) ppic.Grid {
+	validateExpected(source)
 
-			grid[y][x] = c == '#'
-		}
+	grid, err := ppic.ParseGrid(strings.Join(source[:], "\n"))
+
+	if err != nil {
+		panic(err)
 	}
 
-	return
+	return grid
 }
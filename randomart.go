@@ -0,0 +1,228 @@
+package ppic
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// The dimensions of the randomart field.
+const (
+	RandomartWidth  = 17
+	RandomartHeight = 9
+)
+
+// randomartSymbols are the characters used to draw the field, indexed by the number of times a cell was visited.
+//
+// The last two characters mark the start and end positions (RandomartStart and RandomartEnd).
+const randomartSymbols = " .o+=*BOX@%&#/^SE"
+
+// Special values of cells in the randomart field.
+const (
+	RandomartStart uint8 = 15
+	RandomartEnd   uint8 = 16
+)
+
+// ErrInvalidFingerprint is an error caused by specifying a fingerprint which could not be parsed.
+var ErrInvalidFingerprint = errors.New("invalid fingerprint")
+
+// Randomart is a field generated using the "drunken bishop" algorithm used by OpenSSH to visualize key fingerprints.
+//
+// Each cell contains the number of times it was visited (up to RandomartStart-1), with the start and end positions
+// set to RandomartStart and RandomartEnd.
+type Randomart [RandomartHeight][RandomartWidth]uint8
+
+// GenerateRandomart returns the randomart field for the provided fingerprint digest.
+func GenerateRandomart(digest []byte) (r Randomart) {
+	// The bishop starts in the middle of the field.
+	x := RandomartWidth / 2
+	y := RandomartHeight / 2
+
+	for _, b := range digest {
+		// Each byte makes 4 moves, using 2 bits (starting with the least significant) for each one.
+		for i := 0; i < 4; i++ {
+			if b&0x1 != 0 {
+				x++
+			} else {
+				x--
+			}
+
+			if b&0x2 != 0 {
+				y++
+			} else {
+				y--
+			}
+
+			// The bishop can't move outside of the field.
+			x = clamp(x, 0, RandomartWidth-1)
+			y = clamp(y, 0, RandomartHeight-1)
+
+			if r[y][x] < RandomartStart-1 {
+				r[y][x]++
+			}
+
+			b >>= 2
+		}
+	}
+
+	r[RandomartHeight/2][RandomartWidth/2] = RandomartStart
+	r[y][x] = RandomartEnd
+
+	return r
+}
+
+// clamp restricts a value to be between lo and hi (inclusive).
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
+
+// randomartBorder returns a horizontal border containing a label, centered in the same way as OpenSSH.
+func randomartBorder(label string) string {
+	pad := (RandomartWidth - len(label)) / 2
+
+	return "+" + strings.Repeat("-", pad) + label + strings.Repeat("-", RandomartWidth-pad-len(label)) + "+"
+}
+
+// Format returns the field as text, matching the output of ssh-keygen byte-for-byte.
+//
+// The key type (e.g. "ED25519") and size in bits are shown in the top border and the hash algorithm (e.g. "SHA256")
+// in the bottom border. Both borders are left empty if their labels are empty.
+func (r Randomart) Format(keyType string, bits int, alg string) string {
+	title := ""
+
+	if len(keyType) > 0 {
+		title = fmt.Sprintf("[%s %d]", keyType, bits)
+
+		// If the type and size don't fit then we only show the type (which is truncated if that still doesn't fit).
+		if len(title) > RandomartWidth {
+			title = fmt.Sprintf("[%s]", keyType)
+		}
+
+		if len(title) > RandomartWidth-1 {
+			title = title[:RandomartWidth-1]
+		}
+	}
+
+	hash := ""
+
+	if len(alg) > 0 {
+		hash = fmt.Sprintf("[%s]", alg)
+	}
+
+	buf := bytes.Buffer{}
+
+	buf.WriteString(randomartBorder(title))
+	buf.WriteByte('\n')
+
+	for _, row := range r {
+		buf.WriteByte('|')
+
+		for _, v := range row {
+			buf.WriteByte(randomartSymbols[v])
+		}
+
+		buf.WriteString("|\n")
+	}
+
+	buf.WriteString(randomartBorder(hash))
+
+	return buf.String()
+}
+
+// String returns the field as text, without any labels in the borders.
+func (r Randomart) String() string {
+	return r.Format("", 0, "")
+}
+
+// ParseFingerprint parses a fingerprint in the format used by ssh-keygen, returning the hash algorithm and digest.
+//
+// SHA256 fingerprints ("SHA256:" followed by unpadded base64) and MD5 fingerprints ("MD5:" followed by colon separated
+// hex, with the prefix being optional) are supported.
+func ParseFingerprint(fp string) (alg string, digest []byte, err error) {
+	switch {
+	case strings.HasPrefix(fp, "SHA256:"):
+		digest, err = base64.RawStdEncoding.DecodeString(fp[len("SHA256:"):])
+
+		if err != nil || len(digest) != sha256.Size {
+			return "", nil, ErrInvalidFingerprint
+		}
+
+		return "SHA256", digest, nil
+	default:
+		digest, err = hex.DecodeString(strings.Replace(strings.TrimPrefix(fp, "MD5:"), ":", "", -1))
+
+		if err != nil || len(digest) != md5.Size {
+			return "", nil, ErrInvalidFingerprint
+		}
+
+		return "MD5", digest, nil
+	}
+}
+
+// randomartDigest returns the hash algorithm and digest for a key, which is either parsed as a fingerprint or hashed
+// using SHA256 if it isn't one.
+func randomartDigest(k string) (string, []byte) {
+	if alg, digest, err := ParseFingerprint(k); err == nil {
+		return alg, digest
+	}
+
+	return "SHA256", hashBytes(k)
+}
+
+// randomartPalette returns the palette used for randomart images, fading from the background to the foreground as
+// cells are visited more often and using darker shades of the foreground for the start and end positions.
+func randomartPalette(p Palette) color.Palette {
+	pal := make(color.Palette, len(randomartSymbols))
+
+	for i := uint8(0); i < RandomartStart; i++ {
+		pal[i] = mixColors(p.Background, p.Foreground, float64(i)/float64(RandomartStart-1))
+	}
+
+	pal[RandomartStart] = mixColors(p.Foreground, color.Black, 0.5)
+	pal[RandomartEnd] = mixColors(p.Foreground, color.Black, 0.75)
+
+	return pal
+}
+
+// GenerateRandomartImage returns an image for the specified randomart field.
+//
+// The field is drawn using square cells, centered vertically within a square image.
+func GenerateRandomartImage(r Randomart, size int, p Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	// The size of each cell, and the offset needed to center the field.
+	cSize := size / RandomartWidth
+	oX := (size - cSize*RandomartWidth) / 2
+	oY := (size - cSize*RandomartHeight) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), randomartPalette(p))
+
+	for y, row := range r {
+		for x, val := range row {
+			for cY := oY + y*cSize; cY < oY+(y+1)*cSize; cY++ {
+				for cX := oX + x*cSize; cX < oX+(x+1)*cSize; cX++ {
+					img.Pix[cY*img.Stride+cX] = val
+				}
+			}
+		}
+	}
+
+	return img, nil
+}
@@ -0,0 +1,291 @@
+package ppic_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+// randomartCases contains the output of "ssh-keygen -lv" for a set of keys.
+var randomartCases = []struct {
+	keyType     string
+	bits        int
+	fingerprint string
+	expected    []string
+}{
+	{
+		keyType:     "ECDSA",
+		bits:        256,
+		fingerprint: "SHA256:7JN+45/sYfGvlh6cCZEqbhtf8gWACQi7XMLAkIZ0clY",
+		expected: []string{
+			"+---[ECDSA 256]---+",
+			"|B+.=.E.          |",
+			"|o==..  . o   .   |",
+			"|. + .   o . o    |",
+			"| . +   .   o .   |",
+			"|  o     S . +    |",
+			"|       o o   * o |",
+			"|        B . + B. |",
+			"|       o =o* +oo |",
+			"|        ooo+*oo..|",
+			"+----[SHA256]-----+",
+		},
+	},
+	{
+		keyType:     "ECDSA",
+		bits:        256,
+		fingerprint: "MD5:e4:ef:9e:d8:53:3c:d4:f9:b7:b4:4e:ce:3f:20:48:d0",
+		expected: []string{
+			"+---[ECDSA 256]---+",
+			"|        .        |",
+			"|       . E       |",
+			"|        o    . . |",
+			"|       o .  . o  |",
+			"|        S .o   . |",
+			"|         o .+. .o|",
+			"|          ....o.+|",
+			"|         +..  ++ |",
+			"|        ..=.  .++|",
+			"+------[MD5]------+",
+		},
+	},
+	{
+		keyType:     "ECDSA",
+		bits:        521,
+		fingerprint: "SHA256:K0RAr3Sc7grmpjJTkJusp1ejFqnia0L6qAw3de5VscM",
+		expected: []string{
+			"+---[ECDSA 521]---+",
+			"|   .o            |",
+			"|     + .         |",
+			"| .  . *   .      |",
+			"|o  . =   . o     |",
+			"|.+ .o + S E      |",
+			"|oo+.o=   o .     |",
+			"|=o=+ .+ o        |",
+			"|%**o o o         |",
+			"|%#+ . .          |",
+			"+----[SHA256]-----+",
+		},
+	},
+	{
+		keyType:     "ECDSA",
+		bits:        521,
+		fingerprint: "MD5:5a:5a:8b:06:1a:a5:82:0c:8a:af:f9:32:0a:97:78:35",
+		expected: []string{
+			"+---[ECDSA 521]---+",
+			"|                 |",
+			"|                 |",
+			"|.   .            |",
+			"|*  o             |",
+			"|=.o E   S        |",
+			"| + = o * .       |",
+			"|o *   = .        |",
+			"|+=   .           |",
+			"|=+.              |",
+			"+------[MD5]------+",
+		},
+	},
+	{
+		keyType:     "ED25519",
+		bits:        256,
+		fingerprint: "SHA256:mSMKTEkh4f08bOVb2ePNoJeoGGRBfjHAiw0gWQfRxNE",
+		expected: []string{
+			"+--[ED25519 256]--+",
+			"|+=BB==.o         |",
+			"|+o.+=.E o        |",
+			"| .o.+o.o         |",
+			"| o .+o=  oo      |",
+			"|  o  O..So +     |",
+			"|   .+...o.+ *    |",
+			"|    .. . o + o   |",
+			"|      o . .      |",
+			"|     . .         |",
+			"+----[SHA256]-----+",
+		},
+	},
+	{
+		keyType:     "ED25519",
+		bits:        256,
+		fingerprint: "MD5:1b:7c:b3:71:a6:cb:7f:09:00:29:c3:be:b3:46:f5:16",
+		expected: []string{
+			"+--[ED25519 256]--+",
+			"|     .   .       |",
+			"|      + o        |",
+			"|     . o .       |",
+			"|      ... E      |",
+			"|       oS.+oo    |",
+			"|      +  +oB.    |",
+			"|     . o..o  . . |",
+			"|      o  . .  o  |",
+			"|     .    o...   |",
+			"+------[MD5]------+",
+		},
+	},
+	{
+		keyType:     "RSA",
+		bits:        1024,
+		fingerprint: "SHA256:S+7bEVgQuhv3ZIHEmp3ra5TLIxSOOCG93REdtWOC0eo",
+		expected: []string{
+			"+---[RSA 1024]----+",
+			"|      .+=+.      |",
+			"|      .=+o .     |",
+			"| .    o*o.*      |",
+			"|. o   *.o= o     |",
+			"| . = =ooSo+      |",
+			"|  + o E*+= .     |",
+			"|   . ..+o.o      |",
+			"|      ..*. .     |",
+			"|       o++.      |",
+			"+----[SHA256]-----+",
+		},
+	},
+	{
+		keyType:     "RSA",
+		bits:        1024,
+		fingerprint: "MD5:03:46:15:c9:27:fc:01:4f:6a:87:67:f7:91:a0:6d:1a",
+		expected: []string{
+			"+---[RSA 1024]----+",
+			"|      .+++. .    |",
+			"|     .  ==oo . . |",
+			"|      o ++E.+ o  |",
+			"|     . o +.= . . |",
+			"|        S .   .  |",
+			"|         .       |",
+			"|                 |",
+			"|                 |",
+			"|                 |",
+			"+------[MD5]------+",
+		},
+	}}
+
+func TestGenerateRandomart(t *testing.T) {
+	for _, c := range randomartCases {
+		c := c
+
+		t.Run(c.fingerprint, func(t *testing.T) {
+			alg, digest, err := ppic.ParseFingerprint(c.fingerprint)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := strings.Join(c.expected, "\n")
+			actual := ppic.GenerateRandomart(digest).Format(c.keyType, c.bits, alg)
+
+			if actual != expected {
+				t.Errorf("expected randomart to be\n%s\nbut got\n%s", expected, actual)
+			}
+		})
+	}
+}
+
+func TestRandomartFormatTitle(t *testing.T) {
+	cases := []struct {
+		keyType string
+		bits    int
+		border  string
+	}{
+		{"", 0, "+-----------------+"},
+		{"RSA", 4096, "+---[RSA 4096]----+"},
+		{"ED25519-CERT", 256, "+-[ED25519-CERT]--+"},
+		{"ECDSA-SK-CERT", 256, "+-[ECDSA-SK-CERT]-+"},
+		{"SK-SSH-ED25519-CERT", 256, "+[SK-SSH-ED25519--+"},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.keyType, func(t *testing.T) {
+			art := ppic.Randomart{}.Format(c.keyType, c.bits, "")
+
+			if border := art[:strings.IndexByte(art, '\n')]; border != c.border {
+				t.Errorf("expected border to be %q but got %q", c.border, border)
+			}
+		})
+	}
+}
+
+func TestParseFingerprint(t *testing.T) {
+	cases := []struct {
+		fingerprint string
+		alg         string
+		valid       bool
+	}{
+		{"SHA256:7JN+45/sYfGvlh6cCZEqbhtf8gWACQi7XMLAkIZ0clY", "SHA256", true},
+		{"MD5:e4:ef:9e:d8:53:3c:d4:f9:b7:b4:4e:ce:3f:20:48:d0", "MD5", true},
+		{"e4:ef:9e:d8:53:3c:d4:f9:b7:b4:4e:ce:3f:20:48:d0", "MD5", true},
+		{"SHA256:7JN+45/sYfGvlh6cCZEqbhtf8gWACQi7XMLAkIZ0cl", "", false},
+		{"SHA256:not base64!", "", false},
+		{"e4:ef:9e:d8", "", false},
+		{"jackwilsdon", "", false},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.fingerprint, func(t *testing.T) {
+			alg, _, err := ppic.ParseFingerprint(c.fingerprint)
+
+			if !c.valid {
+				if err != ppic.ErrInvalidFingerprint {
+					t.Errorf("expected error to be %q but got %v", ppic.ErrInvalidFingerprint, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if alg != c.alg {
+				t.Errorf("expected algorithm to be %q but got %q", c.alg, alg)
+			}
+		})
+	}
+}
+
+func TestGenerateRandomartImage(t *testing.T) {
+	_, digest, err := ppic.ParseFingerprint(randomartCases[0].fingerprint)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pal := ppic.Palette{Foreground: color.Black, Background: color.White}
+	art := ppic.GenerateRandomart(digest)
+	img, err := ppic.GenerateRandomartImage(art, 136, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The field is 17 cells wide, so each cell is 8 pixels and the field is centered vertically.
+	oY := (136 - 8*ppic.RandomartHeight) / 2
+
+	cases := []struct {
+		x, y     int
+		expected color.Color
+	}{
+		{0, 0, pal.Background},
+		// The first two rows start with "B" (visited 6 times) and "o" (visited twice).
+		{4, oY + 4, color.RGBA{R: 0x92, G: 0x92, B: 0x92, A: 0xFF}},
+		{4, oY + 12, color.RGBA{R: 0xDB, G: 0xDB, B: 0xDB, A: 0xFF}},
+		// The start position is a darker shade of the foreground.
+		{ppic.RandomartWidth / 2 * 8, oY + ppic.RandomartHeight/2*8, color.RGBA{R: 0, G: 0, B: 0, A: 0xFF}},
+	}
+
+	for _, c := range cases {
+		if act := img.At(c.x, c.y); !colorsEqual(act, c.expected) {
+			t.Errorf("expected (%d, %d) to be %v but got %v", c.x, c.y, c.expected, act)
+		}
+	}
+
+	for _, size := range []int{31, 0, -8} {
+		if _, err := ppic.GenerateRandomartImage(art, size, pal); err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package ppic
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// point is a point on an image, in pixels.
+type point struct {
+	X, Y float64
+}
+
+// fillPolygon fills a polygon on a paletted image using the even-odd rule.
+//
+// Pixels are filled if their center lies inside the polygon, so there is no anti-aliasing.
+func fillPolygon(img *image.Paletted, pts []point, c uint8) {
+	if len(pts) < 3 {
+		return
+	}
+
+	minY, maxY := pts[0].Y, pts[0].Y
+
+	for _, p := range pts[1:] {
+		minY = math.Min(minY, p.Y)
+		maxY = math.Max(maxY, p.Y)
+	}
+
+	b := img.Rect
+	y0 := int(math.Max(math.Floor(minY), float64(b.Min.Y)))
+	y1 := int(math.Min(math.Ceil(maxY), float64(b.Max.Y)))
+	xs := make([]float64, 0, len(pts))
+
+	for y := y0; y < y1; y++ {
+		// Sample through the center of the row.
+		cy := float64(y) + 0.5
+		xs = xs[:0]
+
+		// Find where each edge crosses the row.
+		for i, p := range pts {
+			q := pts[(i+1)%len(pts)]
+
+			if (p.Y <= cy && q.Y > cy) || (q.Y <= cy && p.Y > cy) {
+				xs = append(xs, p.X+(cy-p.Y)*(q.X-p.X)/(q.Y-p.Y))
+			}
+		}
+
+		sort.Float64s(xs)
+
+		// Fill between each pair of crossings.
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0 := int(math.Max(math.Ceil(xs[i]-0.5), float64(b.Min.X)))
+			x1 := int(math.Min(math.Ceil(xs[i+1]-0.5), float64(b.Max.X)))
+
+			for x := x0; x < x1; x++ {
+				img.Pix[(y-b.Min.Y)*img.Stride+(x-b.Min.X)] = c
+			}
+		}
+	}
+}
+
+// maskSubsamples is the number of rows sampled within each pixel when anti-aliasing.
+const maskSubsamples = 16
+
+// span is a horizontal span covered by a polygon.
+type span struct {
+	X0, X1 float64
+}
+
+// polygonSpans appends the spans where the row at y crosses a set of polygons to spans.
+//
+// The polygons are combined using the even-odd rule, so polygons inside other polygons will create holes.
+func polygonSpans(spans []span, polys [][]point, y float64) []span {
+	xs := make([]float64, 0, 8)
+
+	for _, pts := range polys {
+		for i, p := range pts {
+			q := pts[(i+1)%len(pts)]
+
+			if (p.Y <= y && q.Y > y) || (q.Y <= y && p.Y > y) {
+				xs = append(xs, p.X+(y-p.Y)*(q.X-p.X)/(q.Y-p.Y))
+			}
+		}
+	}
+
+	sort.Float64s(xs)
+
+	for i := 0; i+1 < len(xs); i += 2 {
+		spans = append(spans, span{xs[i], xs[i+1]})
+	}
+
+	return spans
+}
+
+// fillMask returns an anti-aliased mask covering the union of the provided shapes, where each shape is a set of
+// polygons combined using the even-odd rule.
+//
+// Each pixel is sampled along multiple rows, with the horizontal coverage of each row calculated exactly.
+func fillMask(r image.Rectangle, shapes [][][]point) *image.Alpha {
+	mask := image.NewAlpha(r)
+	acc := make([]float64, r.Dx())
+	spans := make([]span, 0, len(shapes)*2)
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for i := range acc {
+			acc[i] = 0
+		}
+
+		for s := 0; s < maskSubsamples; s++ {
+			sy := float64(y) + (float64(s)+0.5)/maskSubsamples
+			spans = spans[:0]
+
+			for _, polys := range shapes {
+				spans = polygonSpans(spans, polys, sy)
+			}
+
+			if len(spans) == 0 {
+				continue
+			}
+
+			// Merge any overlapping spans so that overlapping polygons aren't counted twice.
+			sort.Slice(spans, func(i, j int) bool {
+				return spans[i].X0 < spans[j].X0
+			})
+
+			merged := spans[:1]
+
+			for _, sp := range spans[1:] {
+				if last := &merged[len(merged)-1]; sp.X0 <= last.X1 {
+					last.X1 = math.Max(last.X1, sp.X1)
+				} else {
+					merged = append(merged, sp)
+				}
+			}
+
+			// Add the coverage of each span to the pixels it passes through.
+			for _, sp := range merged {
+				x0 := math.Max(sp.X0, float64(r.Min.X))
+				x1 := math.Min(sp.X1, float64(r.Max.X))
+
+				for x := int(math.Floor(x0)); float64(x) < x1; x++ {
+					cover := math.Min(x1, float64(x+1)) - math.Max(x0, float64(x))
+					acc[x-r.Min.X] += cover / maskSubsamples
+				}
+			}
+		}
+
+		for x, a := range acc {
+			mask.Pix[(y-r.Min.Y)*mask.Stride+x] = uint8(math.Round(math.Min(a, 1) * 0xFF))
+		}
+	}
+
+	return mask
+}
+
+// blurBoxes returns the sizes of n box blurs which approximate a gaussian blur with the specified standard deviation.
+//
+// See http://blog.ivank.net/fastest-gaussian-blur.html for details.
+func blurBoxes(sigma float64, n int) []int {
+	wIdeal := math.Sqrt(12*sigma*sigma/float64(n) + 1)
+	wl := int(math.Floor(wIdeal))
+
+	// Box sizes must be odd so that they're centered.
+	if wl%2 == 0 {
+		wl--
+	}
+
+	wu := wl + 2
+	m := int(math.Round((12*sigma*sigma - float64(n*wl*wl) - float64(4*n*wl) - float64(3*n)) / float64(-4*wl-4)))
+
+	boxes := make([]int, n)
+
+	for i := range boxes {
+		if i < m {
+			boxes[i] = wl
+		} else {
+			boxes[i] = wu
+		}
+	}
+
+	return boxes
+}
+
+// boxBlur blurs the values in src into dst along one axis, treating values outside of the data as 0.
+//
+// The data is made up of count lines of length values, with stride being the distance between neighbouring values in
+// a line and step being the distance between neighbouring lines.
+func boxBlur(dst, src []float64, r, length, count, stride, step int) {
+	scale := 1 / float64(r*2+1)
+
+	for l := 0; l < count; l++ {
+		base := l * step
+		sum := 0.0
+
+		// Prime the sum with the values which are in range for the first output.
+		for i := 0; i < r && i < length; i++ {
+			sum += src[base+i*stride]
+		}
+
+		for i := 0; i < length; i++ {
+			if i+r < length {
+				sum += src[base+(i+r)*stride]
+			}
+
+			if i-r-1 >= 0 {
+				sum -= src[base+(i-r-1)*stride]
+			}
+
+			dst[base+i*stride] = sum * scale
+		}
+	}
+}
+
+// blurMask applies an approximate gaussian blur to a mask, treating the area outside of the mask as transparent.
+func blurMask(m *image.Alpha, sigma float64) *image.Alpha {
+	w, h := m.Rect.Dx(), m.Rect.Dy()
+	a := make([]float64, w*h)
+	b := make([]float64, w*h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			a[y*w+x] = float64(m.Pix[y*m.Stride+x])
+		}
+	}
+
+	for _, size := range blurBoxes(sigma, 3) {
+		r := (size - 1) / 2
+
+		boxBlur(b, a, r, w, h, 1, w)
+		boxBlur(a, b, r, h, w, w, 1)
+	}
+
+	out := image.NewAlpha(m.Rect)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			out.Pix[y*out.Stride+x] = uint8(math.Round(math.Max(0, math.Min(0xFF, a[y*w+x]))))
+		}
+	}
+
+	return out
+}
+
+// scaledMask is a mask scaled up from a smaller mask using bilinear interpolation, without storing the scaled mask.
+type scaledMask struct {
+	mask *image.Alpha
+
+	// scale is the size of each pixel of the smaller mask, in pixels.
+	scale float64
+}
+
+// ColorModel returns the color model of the mask.
+func (m scaledMask) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+// Bounds returns the bounds of the scaled mask.
+func (m scaledMask) Bounds() image.Rectangle {
+	b := m.mask.Rect
+
+	return image.Rect(
+		int(math.Floor(float64(b.Min.X)*m.scale)),
+		int(math.Floor(float64(b.Min.Y)*m.scale)),
+		int(math.Ceil(float64(b.Max.X)*m.scale)),
+		int(math.Ceil(float64(b.Max.Y)*m.scale)),
+	)
+}
+
+// At returns the alpha of the mask at (x, y), interpolated between the centers of the nearest pixels of the smaller
+// mask (which are transparent outside of it).
+func (m scaledMask) At(x, y int) color.Color {
+	fx := (float64(x)+0.5)/m.scale - 0.5
+	fy := (float64(y)+0.5)/m.scale - 0.5
+	x0, y0 := math.Floor(fx), math.Floor(fy)
+	tx, ty := fx-x0, fy-y0
+	ix, iy := int(x0), int(y0)
+
+	top := float64(m.mask.AlphaAt(ix, iy).A)*(1-tx) + float64(m.mask.AlphaAt(ix+1, iy).A)*tx
+	bottom := float64(m.mask.AlphaAt(ix, iy+1).A)*(1-tx) + float64(m.mask.AlphaAt(ix+1, iy+1).A)*tx
+
+	return color.Alpha{A: uint8(math.Round(top*(1-ty) + bottom*ty))}
+}
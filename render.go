@@ -0,0 +1,145 @@
+package ppic
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// renderParameters are the query parameters accepted by the render handler, along with the options of the encoder.
+var renderParameters = []string{"bg", "fg", "grid", "size"}
+
+// renderHandler serves HTTP requests with images rendered from grids in the query.
+type renderHandler struct {
+	handler
+}
+
+// NewRenderHandler returns a handler which serves HTTP requests with images rendered from a grid and palette in the
+// query (such as /render.png?grid=a5bd00a52400c381&fg=f00), rather than generated from a key.
+//
+// The grid is parsed using ParseGrid, and the colors are parsed using ParseColor (defaulting to DefaultPalette). Only
+// the encoder options of the configuration are used.
+func NewRenderHandler(config HandlerConfig) http.Handler {
+	return renderHandler{handler{config: config}}
+}
+
+// RenderHandler serves HTTP requests with images rendered from a grid and palette in the query, using the default
+// configuration.
+func RenderHandler(res http.ResponseWriter, req *http.Request) {
+	renderHandler{}.ServeHTTP(res, req)
+}
+
+// hasString returns whether a list of strings contains a string.
+func hasString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ServeHTTP serves an HTTP request with an image rendered from the grid in the query.
+func (h renderHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	// We only support GETing images.
+	if req.Method != http.MethodGet {
+		res.Header().Set("Allow", http.MethodGet)
+		res.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	ext := strings.ToLower(path.Ext(req.URL.Path))
+
+	// Images are returned as PNGs if no extension is specified.
+	if len(ext) == 0 {
+		ext = ".png"
+	}
+
+	encoder, ok := LookupEncoder(ext)
+
+	if !ok {
+		res.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(res, "error: unsupported file format")
+
+		return
+	}
+
+	q := req.URL.Query()
+
+	// Reject anything we don't understand, so that mistakes (such as misspelling a parameter) don't go unnoticed.
+	for k, v := range q {
+		if !hasString(renderParameters, k) && !hasString(encoder.Options, k) {
+			res.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(res, "error: unsupported parameter %q", k)
+
+			return
+		}
+
+		if len(v) != 1 {
+			res.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(res, "error: %s specified more than once", k)
+
+			return
+		}
+	}
+
+	if _, ok = q["grid"]; !ok {
+		res.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(res, "error: missing grid")
+
+		return
+	}
+
+	grid, err := ParseGrid(q.Get("grid"))
+
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(res, "error: %s", err)
+
+		return
+	}
+
+	p, err := overridePalette(DefaultPalette, getColors(q))
+
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(res, "error: %s", err)
+
+		return
+	}
+
+	size, err := getImageSize(q)
+
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(res, "error: invalid size")
+
+		return
+	}
+
+	opts, err := h.getEncodeOptions(encoder, q)
+
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(res, "error: %s", err)
+
+		return
+	}
+
+	img, err := GenerateImage(grid, size, p)
+
+	if err != nil {
+		writeGenerateError(res, err)
+
+		return
+	}
+
+	res.Header().Set("Content-Type", encoder.MIMEType)
+
+	if err = encoder.Encode(res, img, opts); err != nil {
+		writeEncodeError(res, err)
+	}
+}
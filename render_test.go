@@ -0,0 +1,134 @@
+package ppic_test
+
+import (
+	"image"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+	"github.com/jackwilsdon/go-ppic/ppictest"
+)
+
+func TestRenderHandler(t *testing.T) {
+	red := ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.RGBA{B: 0x33, A: 0xFF}}
+
+	cases := []struct {
+		path        string
+		statusCode  int
+		contentType string
+		palette     ppic.Palette
+		body        string
+	}{
+		{"/render?grid=a5bd00a52400c381", http.StatusOK, "image/png", ppic.DefaultPalette, ""},
+		{"/render.png?grid=A5BD00A52400C381&size=64", http.StatusOK, "image/png", ppic.DefaultPalette, ""},
+		{"/render.gif?grid=a5bd00a52400c381&fg=%23f00&bg=003", http.StatusOK, "image/gif", red, ""},
+		{"/render.png?grid=a5bd00a52400c381&fg=ff0000ff&bg=%23000033", http.StatusOK, "image/png", red, ""},
+		{"/render.png?grid=a5bd00a52400c381&fg=red&bg=" + url.QueryEscape("rgb(0, 0, 20%)"), http.StatusOK, "image/png", red,
+			""},
+		{"/render.png?grid=" + url.QueryEscape(strings.Join(testGrid[:], "\n")), http.StatusOK, "image/png",
+			ppic.DefaultPalette, ""},
+		{"/render.jpg?grid=a5bd00a52400c381&quality=90", http.StatusOK, "image/jpeg", ppic.Palette{}, ""},
+		{"/render.bmp?grid=a5bd00a52400c381", http.StatusNotFound, "", ppic.Palette{}, "error: unsupported file format"},
+		{"/render", http.StatusBadRequest, "", ppic.Palette{}, "error: missing grid"},
+		{"/render?grid=foo", http.StatusBadRequest, "", ppic.Palette{}, "error: invalid grid"},
+		{"/render?grid=a5bd00a52400c381&grid=0", http.StatusBadRequest, "", ppic.Palette{},
+			"error: grid specified more than once"},
+		{"/render?grid=a5bd00a52400c381&style=github", http.StatusBadRequest, "", ppic.Palette{},
+			"error: unsupported parameter \"style\""},
+		{"/render.png?grid=a5bd00a52400c381&quality=90", http.StatusBadRequest, "", ppic.Palette{},
+			"error: unsupported parameter \"quality\""},
+		{"/render?grid=a5bd00a52400c381&fg=f00f0", http.StatusBadRequest, "", ppic.Palette{},
+			"error: invalid fg color \"f00f0\""},
+		{"/render?grid=a5bd00a52400c381&bg=ggg", http.StatusBadRequest, "", ppic.Palette{},
+			"error: invalid bg color \"ggg\""},
+		{"/render?grid=a5bd00a52400c381&size=12", http.StatusBadRequest, "", ppic.Palette{},
+			"error: size must be a multiple of 8"},
+		{"/render?grid=a5bd00a52400c381&size=-8", http.StatusBadRequest, "", ppic.Palette{}, "error: invalid size"},
+		{"/render?grid=a5bd00a52400c381&compression=foo", http.StatusBadRequest, "", ppic.Palette{},
+			"error: invalid compression \"foo\""},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.path[1:], func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, c.path, nil)
+
+			if err != nil {
+				t.Fatalf("http.NewRequest: %s", err)
+			}
+
+			rec := httptest.NewRecorder()
+
+			ppic.RenderHandler(rec, req)
+
+			if rec.Code != c.statusCode {
+				t.Fatalf("expected status to be %d but got %d (%s)", c.statusCode, rec.Code, rec.Body.String())
+			}
+
+			if len(c.body) > 0 && rec.Body.String() != c.body {
+				t.Errorf("expected body to be %q but got %q", c.body, rec.Body.String())
+			}
+
+			if len(c.contentType) > 0 {
+				if cType := rec.Header().Get("Content-Type"); cType != c.contentType {
+					t.Errorf("expected content type to be %q but got %q", c.contentType, cType)
+				}
+			}
+
+			if c.palette.Foreground != nil {
+				img, _, err := image.Decode(rec.Body)
+
+				if err != nil {
+					t.Fatalf("failed to decode image: %s", err)
+				}
+
+				if err = ppictest.CompareImage(img, c.palette, testGrid); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderHandlerMethod(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "/render?grid=a5bd00a52400c381", nil)
+
+	if err != nil {
+		t.Fatalf("http.NewRequest: %s", err)
+	}
+
+	rec := httptest.NewRecorder()
+
+	ppic.RenderHandler(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status to be %d but got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+func TestNewRenderHandler(t *testing.T) {
+	h := ppic.NewRenderHandler(ppic.HandlerConfig{
+		EncodeLimits: map[string]map[string]ppic.EncodeLimit{
+			"jpeg": {"quality": {Min: 10, Max: 90}},
+		},
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "/render.jpg?grid=a5bd00a52400c381&quality=95", nil)
+
+	if err != nil {
+		t.Fatalf("http.NewRequest: %s", err)
+	}
+
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status to be %d but got %d", http.StatusBadRequest, rec.Code)
+	}
+}
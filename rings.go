@@ -0,0 +1,155 @@
+package ppic
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// ringsPadding is the amount of space around the outer ring, as a fraction of the image size.
+const ringsPadding = 0.04
+
+// ringsArcSteps is the number of points used to approximate a full circle.
+const ringsArcSteps = 96
+
+// ringsSegments is the number of segments in each ring, starting from the center disc.
+var ringsSegments = []int{1, 6, 12, 18, 24}
+
+// Rings describes a set of concentric rings split into segments, starting from the center disc.
+//
+// Each ring is split into segments of equal size, with the first segment starting at the top and continuing
+// clockwise.
+type Rings [][]bool
+
+// GenerateRings returns a set of rings based on the provided source text, with each segment being filled based on a
+// bit of the hash.
+func GenerateRings(k string) Rings {
+	hsh := hashBytes(k)
+	r := make(Rings, len(ringsSegments))
+	bit := 0
+
+	for i, n := range ringsSegments {
+		r[i] = make([]bool, n)
+
+		for j := range r[i] {
+			r[i][j] = hsh[bit/8]>>uint(bit%8)&0x1 != 0
+			bit++
+		}
+	}
+
+	return r
+}
+
+// arc returns the points along an arc centered at (cx, cy), from angle a0 to a1 (in radians, clockwise from the
+// top).
+func arc(cx, cy, r, a0, a1 float64) []point {
+	n := int(math.Ceil(ringsArcSteps*math.Abs(a1-a0)/(2*math.Pi))) + 1
+	pts := make([]point, n)
+
+	for i := range pts {
+		a := a0 + (a1-a0)*float64(i)/float64(n-1)
+		pts[i] = point{cx + r*math.Sin(a), cy - r*math.Cos(a)}
+	}
+
+	return pts
+}
+
+// shapes returns the shapes making up the filled segments of the rings, in pixels.
+//
+// Runs of neighbouring filled segments are merged into a single shape so that there are no seams between them.
+func (r Rings) shapes(size int) [][][]point {
+	var shapes [][][]point
+
+	c := float64(size) / 2
+	width := c * (1 - ringsPadding*2) / float64(len(r))
+
+	for i, ring := range r {
+		r0, r1 := float64(i)*width, float64(i+1)*width
+		n := len(ring)
+
+		// Find an empty segment to start from, so that runs don't wrap around.
+		start := -1
+
+		for j, filled := range ring {
+			if !filled {
+				start = j
+				break
+			}
+		}
+
+		// A completely filled ring is drawn as a pair of circles (or a single circle for the center disc).
+		if start == -1 {
+			s := [][]point{arc(c, c, r1, 0, 2*math.Pi)}
+
+			if r0 > 0 {
+				s = append(s, arc(c, c, r0, 0, 2*math.Pi))
+			}
+
+			shapes = append(shapes, s)
+
+			continue
+		}
+
+		for j := 1; j <= n; j++ {
+			if !ring[(start+j)%n] {
+				continue
+			}
+
+			// Extend the run as far as it goes.
+			end := j
+
+			for end+1 <= n && ring[(start+end+1)%n] {
+				end++
+			}
+
+			a0 := 2 * math.Pi * float64(start+j) / float64(n)
+			a1 := 2 * math.Pi * float64(start+end+1) / float64(n)
+
+			pts := arc(c, c, r1, a0, a1)
+
+			// The inner edge runs back the other way, or meets at the center for the center disc.
+			if r0 > 0 {
+				pts = append(pts, arc(c, c, r0, a1, a0)...)
+			} else {
+				pts = append(pts, point{c, c})
+			}
+
+			shapes = append(shapes, [][]point{pts})
+			j = end
+		}
+	}
+
+	return shapes
+}
+
+// GenerateRingsImage returns an anti-aliased image for the specified rings.
+func GenerateRingsImage(r Rings, size int, p Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Rect, image.NewUniform(p.Background), image.Point{}, draw.Src)
+
+	if shapes := r.shapes(size); len(shapes) > 0 {
+		mask := fillMask(img.Rect, shapes)
+		draw.DrawMask(img, img.Rect, image.NewUniform(p.Foreground), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+
+	return img, nil
+}
+
+// GenerateRingsSVG returns an SVG document for the specified rings.
+func GenerateRingsSVG(r Rings, size int, p Palette) (string, error) {
+	if size <= 0 || size%8 != 0 {
+		return "", ErrInvalidSize
+	}
+
+	b := newSVGBuilder(size, p.Background, "")
+
+	for _, s := range r.shapes(size) {
+		b.path(p.Foreground, s)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,147 @@
+package ppic_test
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+// fullRings returns a set of rings with every segment set to filled.
+func fullRings(filled bool) ppic.Rings {
+	r := ppic.GenerateRings("jackwilsdon")
+
+	for i := range r {
+		for j := range r[i] {
+			r[i][j] = filled
+		}
+	}
+
+	return r
+}
+
+func TestGenerateRings(t *testing.T) {
+	r := ppic.GenerateRings("jackwilsdon")
+
+	if len(r) != 5 {
+		t.Fatalf("expected 5 rings but got %d", len(r))
+	}
+
+	// The center disc is a single segment, with each ring having more segments than the previous one.
+	if len(r[0]) != 1 {
+		t.Errorf("expected center disc to have 1 segment but got %d", len(r[0]))
+	}
+
+	filled := 0
+
+	for i, ring := range r {
+		if i > 0 && len(ring) <= len(r[i-1]) {
+			t.Errorf("expected ring %d to have more than %d segments but got %d", i, len(r[i-1]), len(ring))
+		}
+
+		for _, f := range ring {
+			if f {
+				filled++
+			}
+		}
+	}
+
+	if filled == 0 {
+		t.Error("expected some segments to be filled")
+	}
+}
+
+func TestGenerateRingsImage(t *testing.T) {
+	pal := ppic.Palette{Foreground: color.Black, Background: color.White}
+
+	// Fill the center disc and the first half of every other ring.
+	r := fullRings(false)
+	r[0][0] = true
+
+	for i := 1; i < len(r); i++ {
+		for j := 0; j < len(r[i])/2; j++ {
+			r[i][j] = true
+		}
+	}
+
+	img, err := ppic.GenerateRingsImage(r, 256, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The radius of the outer ring.
+	outer := 128 * (1 - 0.04*2)
+
+	for _, c := range []struct {
+		x, y int
+		exp  color.Color
+	}{
+		{128, 128, pal.Foreground},
+		{0, 0, pal.Background},
+		{255, 255, pal.Background},
+		{128 + int(outer) - 2, 128, pal.Foreground},
+		{128 - int(outer) + 2, 128, pal.Background},
+		{128 + int(outer) + 2, 128, pal.Background},
+		{128, 128 - int(outer) - 2, pal.Background},
+		{128 + int(outer/math.Sqrt2) - 2, 128 + int(outer/math.Sqrt2) - 2, pal.Foreground},
+	} {
+		if act := img.At(c.x, c.y); !colorsEqual(act, c.exp) {
+			t.Errorf("expected (%d, %d) to be %v but got %v", c.x, c.y, c.exp, act)
+		}
+	}
+}
+
+func TestGenerateRingsImageWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateRingsImage(ppic.GenerateRings("jackwilsdon"), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
+
+func TestGenerateRingsSVG(t *testing.T) {
+	cases := []struct {
+		name  string
+		rings ppic.Rings
+		paths int
+	}{
+		{"empty", fullRings(false), 0},
+		{"full", fullRings(true), 5},
+		{"generated", ppic.GenerateRings("jackwilsdon"), -1},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			svg, err := ppic.GenerateRingsSVG(c.rings, 64, ppic.DefaultPalette)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>") {
+				t.Errorf("expected an SVG document but got %q", svg)
+			}
+
+			if n := strings.Count(svg, "<path"); c.paths >= 0 && n != c.paths {
+				t.Errorf("expected %d paths but got %d", c.paths, n)
+			}
+		})
+	}
+}
+
+func TestGenerateRingsSVGWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateRingsSVG(ppic.GenerateRings("jackwilsdon"), size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package ppic
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultStyle is the name of the style used when no style is specified.
+const DefaultStyle = "default"
+
+// ErrUnsupportedStyle is an error caused by specifying a style which doesn't exist, or which doesn't support the
+// requested output.
+var ErrUnsupportedStyle = errors.New("unsupported style")
+
+// StyleOptionError is returned by styles when an option has an invalid value.
+type StyleOptionError struct {
+	Option string
+	Value  string
+}
+
+// Error returns a description of the invalid option.
+func (e *StyleOptionError) Error() string {
+	return fmt.Sprintf("invalid %s %q", e.Option, e.Value)
+}
+
+// Style generates images from keys.
+//
+// Styles can optionally implement SVGStyle, TextStyle and ConfigurableStyle to support more outputs and options.
+type Style interface {
+	// Palette returns the palette to use for a key.
+	Palette(k string) Palette
+
+	// Image returns an image for a key, using the provided palette.
+	Image(k string, size int, p Palette) (image.Image, error)
+}
+
+// SVGStyle is implemented by styles which can generate SVG documents.
+type SVGStyle interface {
+	Style
+
+	// SVG returns an SVG document for a key, using the provided palette.
+	SVG(k string, size int, p Palette) (string, error)
+}
+
+// TextStyle is implemented by styles which can generate a text representation of a key.
+type TextStyle interface {
+	Style
+
+	// Text returns a text representation of a key.
+	Text(k string) string
+}
+
+// ConfigurableStyle is implemented by styles which accept options (such as the query parameters of a request).
+type ConfigurableStyle interface {
+	Style
+
+	// Configure returns a copy of the style configured using the provided options, ignoring any options which aren't
+	// recognised by the style.
+	Configure(opts map[string]string) (Style, error)
+}
+
+// styles contains the registered styles, indexed by name, starting with the built-in styles.
+var styles = struct {
+	sync.RWMutex
+	styles map[string]Style
+}{styles: map[string]Style{
+	DefaultStyle: pixelStyle{},
+	"blockies":   blockiesStyle{},
+	"geometric":  geometricStyle{},
+	"github":     githubStyle{},
+	"initials":   initialsStyle{},
+	"isometric":  isometricStyle{},
+	"marble":     marbleStyle{},
+	"parts":      partsStyle{set: RobotParts},
+	"randomart":  randomartStyle{},
+	"rings":      ringsStyle{},
+}}
+
+// RegisterStyle registers a style under the specified name, replacing any existing style with the same name.
+//
+// Styles are usually registered by a package's init function, so that importing the package makes the style available
+// to Handler and the ppic and ppicd commands.
+func RegisterStyle(name string, s Style) {
+	styles.Lock()
+	defer styles.Unlock()
+
+	styles.styles[strings.ToLower(name)] = s
+}
+
+// LookupStyle returns the style registered under the specified name, or the default style if the name is empty.
+func LookupStyle(name string) (Style, bool) {
+	if len(name) == 0 {
+		name = DefaultStyle
+	}
+
+	styles.RLock()
+	defer styles.RUnlock()
+
+	s, ok := styles.styles[strings.ToLower(name)]
+
+	return s, ok
+}
+
+// Styles returns the names of the registered styles, in alphabetical order.
+func Styles() []string {
+	styles.RLock()
+	defer styles.RUnlock()
+
+	names := make([]string, 0, len(styles.styles))
+
+	for name := range styles.styles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// StylePalette returns the palette for a key, which is generated by the style (or is DefaultPalette if monochrome is
+// set), with either of the colors replaced by the colors in the map ("fg" and "bg", in any format supported by
+// ParseColor).
+//
+// This is how Handler picks the colors of each image, so using it gives images the same colors as the handler.
+func StylePalette(s Style, k string, monochrome bool, colors map[string]string) (Palette, error) {
+	p := DefaultPalette
+
+	if !monochrome {
+		p = s.Palette(k)
+	}
+
+	return overridePalette(p, colors)
+}
+
+// ConfigureStyle configures a style using the provided options if it implements ConfigurableStyle, and otherwise
+// returns it unchanged.
+func ConfigureStyle(s Style, opts map[string]string) (Style, error) {
+	if c, ok := s.(ConfigurableStyle); ok {
+		return c.Configure(opts)
+	}
+
+	return s, nil
+}
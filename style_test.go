@@ -0,0 +1,233 @@
+package ppic_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+// solidStyle is a style which generates images filled with the foreground color.
+type solidStyle struct {
+	text string
+}
+
+func (solidStyle) Palette(k string) ppic.Palette {
+	return ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: color.White}
+}
+
+func (solidStyle) Image(k string, size int, p ppic.Palette) (image.Image, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, ppic.ErrInvalidSize
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{p.Foreground})
+
+	return img, nil
+}
+
+func (s solidStyle) Text(k string) string {
+	return s.text + k
+}
+
+func (s solidStyle) Configure(opts map[string]string) (ppic.Style, error) {
+	s.text = opts["prefix"]
+
+	return s, nil
+}
+
+func TestStyles(t *testing.T) {
+	names := ppic.Styles()
+
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("expected styles to be sorted but got %v", names)
+	}
+
+	builtin := []string{
+		ppic.DefaultStyle, "blockies", "geometric", "github", "initials", "isometric", "marble", "parts", "randomart",
+		"rings",
+	}
+
+	for _, name := range builtin {
+		if _, ok := ppic.LookupStyle(name); !ok {
+			t.Errorf("expected style %q to be registered", name)
+		}
+	}
+
+	def, _ := ppic.LookupStyle(ppic.DefaultStyle)
+
+	if s, ok := ppic.LookupStyle(""); !ok || s != def {
+		t.Errorf("expected empty style to be the default style but got %v", s)
+	}
+
+	if _, ok := ppic.LookupStyle("foo"); ok {
+		t.Error("expected style \"foo\" not to be registered")
+	}
+}
+
+func TestStylesWithInvalidSize(t *testing.T) {
+	for _, name := range ppic.Styles() {
+		s, _ := ppic.LookupStyle(name)
+
+		for _, size := range []int{31, 0, -8} {
+			img, err := s.Image("example", size, ppic.DefaultPalette)
+
+			if err != ppic.ErrInvalidSize {
+				t.Errorf("expected error for %s style with size %d to be %q but got %v", name, size, ppic.ErrInvalidSize, err)
+			}
+
+			// A typed nil would be a non-nil image.Image.
+			if img != nil {
+				t.Errorf("expected image for %s style with size %d to be nil but got %T", name, size, img)
+			}
+		}
+	}
+}
+
+func TestConfigureStyle(t *testing.T) {
+	def, _ := ppic.LookupStyle(ppic.DefaultStyle)
+
+	// Styles which can't be configured are returned as they are.
+	if s, err := ppic.ConfigureStyle(def, map[string]string{"foo": "bar"}); err != nil || s != def {
+		t.Errorf("expected default style to be returned unchanged but got %v (%v)", s, err)
+	}
+
+	s, err := ppic.ConfigureStyle(solidStyle{}, map[string]string{"prefix": "example-"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if txt := s.(ppic.TextStyle).Text("text"); txt != "example-text" {
+		t.Errorf("expected text to be %q but got %q", "example-text", txt)
+	}
+}
+
+func TestStylePalette(t *testing.T) {
+	red := color.NRGBA{R: 0xFF, A: 0xFF}
+
+	cases := []struct {
+		name       string
+		monochrome bool
+		colors     map[string]string
+		palette    ppic.Palette
+		valid      bool
+	}{
+		{name: "Style", palette: solidStyle{}.Palette(""), valid: true},
+		{name: "Monochrome", monochrome: true, palette: ppic.DefaultPalette, valid: true},
+		{name: "Foreground", monochrome: true, colors: map[string]string{"fg": "red"},
+			palette: ppic.Palette{Foreground: red, Background: color.White}, valid: true},
+		{name: "Background", colors: map[string]string{"bg": "#f00"},
+			palette: ppic.Palette{Foreground: color.RGBA{R: 0xFF, A: 0xFF}, Background: red}, valid: true},
+		{name: "Invalid", colors: map[string]string{"fg": "foo"}},
+		{name: "Empty", colors: map[string]string{"bg": ""}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			p, err := ppic.StylePalette(solidStyle{}, "example", c.monochrome, c.colors)
+
+			if !c.valid {
+				if err == nil {
+					t.Fatalf("expected colors %v to be invalid", c.colors)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if p != c.palette {
+				t.Errorf("expected palette to be %v but got %v", c.palette, p)
+			}
+		})
+	}
+}
+
+func TestRegisterStyle(t *testing.T) {
+	ppic.RegisterStyle("Solid", solidStyle{})
+
+	cases := []struct {
+		path        string
+		contentType string
+		body        string
+	}{
+		{"/example.txt?style=solid&prefix=hello-", "text/plain; charset=utf-8", "hello-example\n"},
+		{"/example.gif?style=SOLID", "image/gif", ""},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.path[1:], func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, c.path, nil)
+
+			if err != nil {
+				t.Fatalf("http.NewRequest: %s", err)
+			}
+
+			rec := httptest.NewRecorder()
+
+			ppic.Handler(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status to be %d but got %d", http.StatusOK, rec.Code)
+			}
+
+			if cType := rec.Header().Get("Content-Type"); cType != c.contentType {
+				t.Errorf("expected content type to be %q but got %q", c.contentType, cType)
+			}
+
+			if len(c.body) > 0 && rec.Body.String() != c.body {
+				t.Errorf("expected body to be %q but got %q", c.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	get := func(h http.Handler, path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+
+		if err != nil {
+			t.Fatalf("http.NewRequest: %s", err)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	h := ppic.NewHandler(ppic.HandlerConfig{Style: "isometric"})
+
+	// Requests without a style use the configured style.
+	exp := get(http.HandlerFunc(ppic.Handler), "/example?style=isometric")
+	act := get(h, "/example")
+
+	if act.Code != http.StatusOK || !bytes.Equal(act.Body.Bytes(), exp.Body.Bytes()) {
+		t.Error("expected the configured style to be used")
+	}
+
+	// Requests can still choose a different style.
+	exp = get(http.HandlerFunc(ppic.Handler), "/example")
+	act = get(h, "/example?style=default")
+
+	if act.Code != http.StatusOK || !bytes.Equal(act.Body.Bytes(), exp.Body.Bytes()) {
+		t.Error("expected the requested style to be used")
+	}
+
+	// Documents are only supported for the default style.
+	if act = get(h, "/example.json"); act.Code != http.StatusBadRequest {
+		t.Errorf("expected status to be %d but got %d", http.StatusBadRequest, act.Code)
+	}
+}
@@ -0,0 +1,248 @@
+package ppic
+
+import (
+	"image"
+	"strconv"
+)
+
+// pixelStyle is the default style, which generates mirrored 8x8 pixel grids.
+type pixelStyle struct{}
+
+// Palette returns the palette generated by GeneratePalette.
+func (pixelStyle) Palette(k string) Palette {
+	return GeneratePalette(k)
+}
+
+// Image returns a GridImage rather than drawing the image, so large images don't need to be stored in memory.
+func (pixelStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	img, err := NewGridImage(Generate(k, true, false), size, p)
+
+	// Returning the *GridImage directly would give a non-nil image.Image when it fails.
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// SVG returns the grid as an SVG document using GenerateSVG.
+func (pixelStyle) SVG(k string, size int, p Palette) (string, error) {
+	return GenerateSVG(Generate(k, true, false), size, p)
+}
+
+// blockiesStyle generates images matching the ethereum-blockies library.
+type blockiesStyle struct{}
+
+// Palette returns the spot color and background color of the key's blockies palette.
+func (blockiesStyle) Palette(k string) Palette {
+	bp := GenerateBlockiesPalette(k)
+
+	return Palette{Foreground: bp.Color, Background: bp.Background}
+}
+
+// Image returns a blockies image, with the palette replacing the spot color and background color of the key.
+func (blockiesStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	bp := GenerateBlockiesPalette(k)
+
+	// The spot color always comes from the key.
+	bp.Color = p.Foreground
+	bp.Background = p.Background
+
+	return GenerateBlockiesImage(GenerateBlockies(k), size, bp)
+}
+
+// geometricStyle generates rotationally symmetric images made up of shapes.
+type geometricStyle struct{}
+
+// Palette returns the palette generated by GenerateGeometricPalette.
+func (geometricStyle) Palette(k string) Palette {
+	return GenerateGeometricPalette(k)
+}
+
+// Image returns the shapes drawn as an image.
+func (geometricStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	return GenerateGeometricImage(GenerateGeometric(k), size, p)
+}
+
+// SVG returns the shapes as an SVG document.
+func (geometricStyle) SVG(k string, size int, p Palette) (string, error) {
+	return GenerateGeometricSVG(GenerateGeometric(k), size, p)
+}
+
+// githubStyle generates 5x5 identicons in the style of GitHub's default avatars.
+type githubStyle struct{}
+
+// Palette returns the palette generated by GenerateGitHubPalette.
+func (githubStyle) Palette(k string) Palette {
+	return GenerateGitHubPalette(k)
+}
+
+// Image returns the 5x5 identicon drawn as an image.
+func (githubStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	return GenerateGitHubImage(GenerateGitHub(k), size, p)
+}
+
+// initialsStyle draws the initials of the key, or of the "name" option if it's set.
+type initialsStyle struct {
+	name string
+}
+
+// Palette returns the palette generated by GenerateInitialsPalette.
+func (initialsStyle) Palette(k string) Palette {
+	return GenerateInitialsPalette(k)
+}
+
+// initials returns the initials to draw, which come from the name if one was specified (but the palette always comes
+// from the key).
+func (s initialsStyle) initials(k string) []string {
+	if len(s.name) > 0 {
+		return Initials(s.name)
+	}
+
+	return Initials(k)
+}
+
+// Image returns the initials drawn using the built-in font.
+func (s initialsStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	return GenerateInitialsImage(s.initials(k), size, p)
+}
+
+// SVG returns the initials as an SVG document, with the glyphs drawn as paths.
+func (s initialsStyle) SVG(k string, size int, p Palette) (string, error) {
+	return GenerateInitialsSVG(s.initials(k), size, p)
+}
+
+// Configure returns a copy of the style which draws the initials of the "name" option.
+func (s initialsStyle) Configure(opts map[string]string) (Style, error) {
+	s.name = opts["name"]
+
+	return s, nil
+}
+
+// isometricStyle draws the default pixel grid as isometric blocks.
+type isometricStyle struct{}
+
+// Palette returns the palette generated by GeneratePalette, as the grid is the same as the default style.
+func (isometricStyle) Palette(k string) Palette {
+	return GeneratePalette(k)
+}
+
+// Image returns the default grid drawn as isometric blocks.
+func (isometricStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	return GenerateIsometricImage(Generate(k, true, false), size, p)
+}
+
+// marbleStyle generates abstract gradients with blurred blobs on top of them.
+type marbleStyle struct{}
+
+// Palette returns the palette generated by GeneratePalette, which the gradient is blended between.
+func (marbleStyle) Palette(k string) Palette {
+	return GeneratePalette(k)
+}
+
+// Image returns the gradient and blobs drawn as an image.
+func (marbleStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	return GenerateMarbleImage(GenerateMarble(k), size, p)
+}
+
+// SVG returns the gradient and blobs as an SVG document using a blur filter.
+func (marbleStyle) SVG(k string, size int, p Palette) (string, error) {
+	return GenerateMarbleSVG(GenerateMarble(k), size, p)
+}
+
+// partsStyle builds images from a part set, chosen using the "set" option (defaulting to the built-in robot parts).
+type partsStyle struct {
+	set PartSet
+}
+
+// Palette returns the palette generated by GeneratePalette, which is used to color the parts.
+func (partsStyle) Palette(k string) Palette {
+	return GeneratePalette(k)
+}
+
+// Image returns the parts chosen for the key layered on top of each other.
+func (s partsStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	return GeneratePartsImage(GenerateParts(k, s.set), size, p)
+}
+
+// Configure returns a copy of the style using the part set named by the "set" option.
+func (s partsStyle) Configure(opts map[string]string) (Style, error) {
+	name := opts["set"]
+
+	if len(name) == 0 {
+		name = "robot"
+	}
+
+	set, ok := LookupPartSet(name)
+
+	if !ok {
+		return nil, &UnknownPartSetError{Name: name}
+	}
+
+	s.set = set
+
+	return s, nil
+}
+
+// randomartStyle draws the OpenSSH randomart visualization of a fingerprint, with the "keytype" and "bits" options
+// setting the title of the text representation.
+type randomartStyle struct {
+	keyType string
+	bits    int
+}
+
+// Palette returns the palette generated by GeneratePalette.
+func (randomartStyle) Palette(k string) Palette {
+	return GeneratePalette(k)
+}
+
+// Image returns the randomart board drawn as an image.
+func (randomartStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	_, digest := randomartDigest(k)
+
+	return GenerateRandomartImage(GenerateRandomart(digest), size, p)
+}
+
+// Text returns the randomart board in the same format as ssh-keygen.
+func (s randomartStyle) Text(k string) string {
+	alg, digest := randomartDigest(k)
+
+	return GenerateRandomart(digest).Format(s.keyType, s.bits, alg)
+}
+
+// Configure returns a copy of the style using the "keytype" and "bits" options, returning an error if bits isn't a
+// number (or is negative).
+func (s randomartStyle) Configure(opts map[string]string) (Style, error) {
+	s.keyType = opts["keytype"]
+	s.bits = 0
+
+	if v := opts["bits"]; len(v) > 0 {
+		bits, err := strconv.Atoi(v)
+
+		if err != nil || bits < 0 {
+			return nil, &StyleOptionError{Option: "bits", Value: v}
+		}
+
+		s.bits = bits
+	}
+
+	return s, nil
+}
+
+// ringsStyle draws concentric rings split into segments.
+type ringsStyle struct{}
+
+// Palette returns the palette generated by GeneratePalette.
+func (ringsStyle) Palette(k string) Palette {
+	return GeneratePalette(k)
+}
+
+// Image returns the rings drawn as an image.
+func (ringsStyle) Image(k string, size int, p Palette) (image.Image, error) {
+	return GenerateRingsImage(GenerateRings(k), size, p)
+}
+
+// SVG returns the rings as an SVG document.
+func (ringsStyle) SVG(k string, size int, p Palette) (string, error) {
+	return GenerateRingsSVG(GenerateRings(k), size, p)
+}
@@ -0,0 +1,108 @@
+package ppic
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// svgNumber formats a number for use in an SVG document, rounded to 2 decimal places and without trailing zeros.
+func svgNumber(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+
+	if s == "-0" {
+		return "0"
+	}
+
+	return s
+}
+
+// svgBuilder builds an SVG document.
+type svgBuilder struct {
+	buf bytes.Buffer
+}
+
+// newSVGBuilder starts a new SVG document of the specified size, filled with the background color (unless it's nil).
+func newSVGBuilder(size int, bg color.Color, attrs string) *svgBuilder {
+	b := &svgBuilder{}
+
+	b.buf.WriteString(`<svg xmlns="http://www.w3.org/2000/svg"`)
+	fmt.Fprintf(&b.buf, ` width="%d" height="%d" viewBox="0 0 %d %d"%s>`, size, size, size, size, attrs)
+
+	if bg != nil {
+		fmt.Fprintf(&b.buf, `<rect width="%d" height="%d" fill="%s"/>`, size, size, hexColor(bg))
+	}
+
+	return b
+}
+
+// path adds a path made up of the provided polygons to the document.
+func (b *svgBuilder) path(c color.Color, polys [][]point) {
+	if len(polys) == 0 {
+		return
+	}
+
+	fmt.Fprintf(&b.buf, `<path fill="%s" fill-rule="evenodd" d="`, hexColor(c))
+
+	for i, pts := range polys {
+		if i > 0 {
+			b.buf.WriteByte(' ')
+		}
+
+		for j, p := range pts {
+			cmd := "L"
+
+			if j == 0 {
+				cmd = "M"
+			}
+
+			fmt.Fprintf(&b.buf, "%s%s %s", cmd, svgNumber(p.X), svgNumber(p.Y))
+		}
+
+		b.buf.WriteByte('Z')
+	}
+
+	b.buf.WriteString(`"/>`)
+}
+
+// String finishes the document and returns it.
+func (b *svgBuilder) String() string {
+	b.buf.WriteString("</svg>")
+
+	return b.buf.String()
+}
+
+// GenerateSVG returns an SVG document for the specified grid.
+func GenerateSVG(grid Grid, size int, p Palette) (string, error) {
+	if size <= 0 || size%8 != 0 {
+		return "", ErrInvalidSize
+	}
+
+	// The size of each pixel in the image.
+	pSize := float64(size / 8)
+
+	polys := make([][]point, 0, 64)
+
+	for y, row := range grid {
+		for x, val := range row {
+			if !val {
+				continue
+			}
+
+			x0, y0 := float64(x)*pSize, float64(y)*pSize
+			x1, y1 := x0+pSize, y0+pSize
+
+			polys = append(polys, []point{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}})
+		}
+	}
+
+	// Make sure that the edges of neighbouring cells line up exactly.
+	b := newSVGBuilder(size, p.Background, ` shape-rendering="crispEdges"`)
+	b.path(p.Foreground, polys)
+
+	return b.String(), nil
+}
@@ -0,0 +1,55 @@
+package ppic_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+func TestGenerateSVG(t *testing.T) {
+	grid := ppic.Grid{}
+	grid[0][0] = true
+	grid[7][1] = true
+
+	pal := ppic.Palette{Foreground: color.Black, Background: color.White}
+	svg, err := ppic.GenerateSVG(grid, 64, pal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `<svg xmlns="http://www.w3.org/2000/svg" width="64" height="64" viewBox="0 0 64 64"` +
+		` shape-rendering="crispEdges">` +
+		`<rect width="64" height="64" fill="#ffffff"/>` +
+		`<path fill="#000000" fill-rule="evenodd" d="M0 0L8 0L8 8L0 8Z M8 56L16 56L16 64L8 64Z"/>` +
+		`</svg>`
+
+	if svg != exp {
+		t.Errorf("expected SVG to be %q but got %q", exp, svg)
+	}
+}
+
+func TestGenerateSVGWithEmptyGrid(t *testing.T) {
+	svg, err := ppic.GenerateSVG(ppic.Grid{}, 64, ppic.DefaultPalette)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An empty grid shouldn't produce an empty path.
+	if strings.Contains(svg, "<path") {
+		t.Errorf("expected SVG not to contain a path but got %q", svg)
+	}
+}
+
+func TestGenerateSVGWithInvalidSize(t *testing.T) {
+	for _, size := range []int{31, 0, -8} {
+		_, err := ppic.GenerateSVG(ppic.Grid{}, size, ppic.DefaultPalette)
+
+		if err != ppic.ErrInvalidSize {
+			t.Errorf("expected error for size %d to be %q but got %v", size, ppic.ErrInvalidSize, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package ppic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/color"
+)
+
+// themeTint is how much white or black is mixed in to create the light and dark variants of a theme color.
+const themeTint = 0.4
+
+// Theme contains colors derived from a palette, for styling elements to match an image.
+type Theme struct {
+	Foreground      color.Color
+	ForegroundLight color.Color
+	ForegroundDark  color.Color
+	Background      color.Color
+	BackgroundLight color.Color
+	BackgroundDark  color.Color
+}
+
+// GenerateTheme generates a theme from a palette.
+func GenerateTheme(p Palette) Theme {
+	return Theme{
+		Foreground:      p.Foreground,
+		ForegroundLight: mixColors(p.Foreground, color.White, themeTint),
+		ForegroundDark:  mixColors(p.Foreground, color.Black, themeTint),
+		Background:      p.Background,
+		BackgroundLight: mixColors(p.Background, color.White, themeTint),
+		BackgroundDark:  mixColors(p.Background, color.Black, themeTint),
+	}
+}
+
+// properties returns the names and values of the CSS custom properties for the theme.
+func (t Theme) properties() [][2]string {
+	return [][2]string{
+		{"--ppic-fg", hexColor(t.Foreground)},
+		{"--ppic-fg-light", hexColor(t.ForegroundLight)},
+		{"--ppic-fg-dark", hexColor(t.ForegroundDark)},
+		{"--ppic-bg", hexColor(t.Background)},
+		{"--ppic-bg-light", hexColor(t.BackgroundLight)},
+		{"--ppic-bg-dark", hexColor(t.BackgroundDark)},
+	}
+}
+
+// CSS returns a CSS rule for the provided selector which defines the theme colors as custom properties.
+func (t Theme) CSS(selector string) string {
+	buf := bytes.Buffer{}
+
+	fmt.Fprintf(&buf, "%s {\n", selector)
+
+	for _, p := range t.properties() {
+		fmt.Fprintf(&buf, "\t%s: %s;\n", p[0], p[1])
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.String()
+}
+
+// MarshalJSON returns the theme as a JSON object mapping CSS custom property names to hex color strings.
+func (t Theme) MarshalJSON() ([]byte, error) {
+	m := make(map[string]string)
+
+	for _, p := range t.properties() {
+		m[p[0]] = p[1]
+	}
+
+	return json.Marshal(m)
+}
@@ -0,0 +1,73 @@
+package ppic_test
+
+import (
+	"encoding/json"
+	"image/color"
+	"testing"
+
+	"github.com/jackwilsdon/go-ppic"
+)
+
+func TestGenerateTheme(t *testing.T) {
+	theme := ppic.GenerateTheme(ppic.Palette{
+		Foreground: color.RGBA{R: 0xFF, G: 0x80, A: 0xFF},
+		Background: color.White,
+	})
+
+	cases := []struct {
+		name     string
+		actual   color.Color
+		expected color.Color
+	}{
+		{"Foreground", theme.Foreground, color.RGBA{R: 0xFF, G: 0x80, A: 0xFF}},
+		{"ForegroundLight", theme.ForegroundLight, color.RGBA{R: 0xFF, G: 0xB3, B: 0x66, A: 0xFF}},
+		{"ForegroundDark", theme.ForegroundDark, color.RGBA{R: 0x99, G: 0x4D, A: 0xFF}},
+		{"Background", theme.Background, color.White},
+		{"BackgroundLight", theme.BackgroundLight, color.White},
+		{"BackgroundDark", theme.BackgroundDark, color.RGBA{R: 0x99, G: 0x99, B: 0x99, A: 0xFF}},
+	}
+
+	for _, c := range cases {
+		if !colorsEqual(c.expected, c.actual) {
+			t.Errorf("expected %s to be %v but got %v", c.name, c.expected, c.actual)
+		}
+	}
+}
+
+func TestThemeCSS(t *testing.T) {
+	css := ppic.GenerateTheme(ppic.DefaultPalette).CSS(".avatar")
+	expected := ".avatar {\n" +
+		"\t--ppic-fg: #000000;\n" +
+		"\t--ppic-fg-light: #666666;\n" +
+		"\t--ppic-fg-dark: #000000;\n" +
+		"\t--ppic-bg: #ffffff;\n" +
+		"\t--ppic-bg-light: #ffffff;\n" +
+		"\t--ppic-bg-dark: #999999;\n" +
+		"}\n"
+
+	if css != expected {
+		t.Errorf("expected CSS to be %q but got %q", expected, css)
+	}
+}
+
+func TestThemeJSON(t *testing.T) {
+	buf, err := json.Marshal(ppic.GenerateTheme(ppic.DefaultPalette))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var props map[string]string
+
+	if err := json.Unmarshal(buf, &props); err != nil {
+		t.Fatal(err)
+	}
+
+	if fg := props["--ppic-fg"]; fg != "#000000" {
+		t.Errorf("expected --ppic-fg to be %q but got %q", "#000000", fg)
+	}
+
+	if bg := props["--ppic-bg"]; bg != "#ffffff" {
+		t.Errorf("expected --ppic-bg to be %q but got %q", "#ffffff", bg)
+	}
+}